@@ -12,11 +12,11 @@ var mux *http.ServeMux
 func init() {
 	// Initialize the application components once
 	repo := repository.NewInMemoryCategoryRepository()
-	svc := service.NewCategoryService(repo)
+	svc := service.NewCategoryService(repo, nil)
 	h := handler.NewCategoryHandler(svc)
 
 	mux = http.NewServeMux()
-	h.RegisterRoutes(mux)
+	h.RegisterRoutes(mux, nil) // No authentication in the in-memory demo
 }
 
 // Handler is the entry point for Vercel Serverless Functions
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Category struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (c *Client) ListCategories(ctx context.Context) ([]Category, error) {
+	var categories []Category
+	err := c.do(ctx, http.MethodGet, "/categories", nil, nil, &categories)
+	return categories, err
+}
+
+func (c *Client) GetCategory(ctx context.Context, id int) (*Category, error) {
+	var category Category
+	if err := c.do(ctx, http.MethodGet, "/categories/"+strconv.Itoa(id), nil, nil, &category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// CreateCategory creates a category from its Name and Description. It is not
+// retried.
+func (c *Client) CreateCategory(ctx context.Context, category Category) (*Category, error) {
+	var created Category
+	if err := c.do(ctx, http.MethodPost, "/categories", nil, category, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) UpdateCategory(ctx context.Context, id int, category Category) (*Category, error) {
+	var updated Category
+	if err := c.do(ctx, http.MethodPut, "/categories/"+strconv.Itoa(id), nil, category, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteCategory(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/categories/"+strconv.Itoa(id), nil, nil, nil)
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentCash    PaymentMethod = "cash"
+	PaymentCard    PaymentMethod = "card"
+	PaymentEWallet PaymentMethod = "ewallet"
+	PaymentQRIS    PaymentMethod = "qris"
+)
+
+type CheckoutItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// CheckoutPayment is what the customer hands over with one method. For cash,
+// Amount may exceed what is owed and the difference is returned as change.
+type CheckoutPayment struct {
+	Method    PaymentMethod `json:"method"`
+	Amount    int           `json:"amount"`
+	Reference string        `json:"reference,omitempty"`
+}
+
+// CheckoutRequest is a sale. Without Payments the total is recorded as paid
+// in exact cash. Stock is taken from LocationID, or from the default location
+// when it is 0, and may come from the caller's reservation ReservationID.
+type CheckoutRequest struct {
+	Items         []CheckoutItem    `json:"items"`
+	LocationID    int               `json:"location_id,omitempty"`
+	ReservationID int               `json:"reservation_id,omitempty"`
+	PromoCodes    []string          `json:"promo_codes,omitempty"`
+	Payments      []CheckoutPayment `json:"payments,omitempty"`
+	// IdempotencyKey is sent as the Idempotency-Key header.
+	IdempotencyKey string `json:"-"`
+}
+
+// Transaction is a completed sale. TotalAmount is what the customer paid,
+// including TaxAmount unless TaxInclusive.
+type Transaction struct {
+	ID             int                 `json:"id"`
+	LocationID     int                 `json:"location_id"`
+	Subtotal       int                 `json:"subtotal"`
+	DiscountAmount int                 `json:"discount_amount"`
+	TaxAmount      int                 `json:"tax_amount"`
+	TaxInclusive   bool                `json:"tax_inclusive"`
+	TotalAmount    int                 `json:"total_amount"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Details        []TransactionDetail `json:"details"`
+	Discounts      []AppliedDiscount   `json:"discounts"`
+	Payments       []Payment           `json:"payments"`
+}
+
+// TransactionDetail is one line of a sale, with the product as it was sold.
+type TransactionDetail struct {
+	ID             int    `json:"id"`
+	TransactionID  int    `json:"transaction_id"`
+	ProductID      int    `json:"product_id"`
+	ProductName    string `json:"product_name"`
+	CategoryID     int    `json:"category_id"`
+	CategoryName   string `json:"category_name"`
+	UnitPrice      int    `json:"unit_price"`
+	UnitCost       int    `json:"unit_cost"`
+	Quantity       int    `json:"quantity"`
+	Subtotal       int    `json:"subtotal"`
+	DiscountAmount int    `json:"discount_amount"`
+	TaxRate        int    `json:"tax_rate"`
+	TaxAmount      int    `json:"tax_amount"`
+}
+
+// AppliedDiscount is one promotion applied to a sale. ProductID is 0 for
+// discounts on the whole sale.
+type AppliedDiscount struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	PromotionID   int    `json:"promotion_id"`
+	Code          string `json:"code,omitempty"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	ProductID     int    `json:"product_id,omitempty"`
+	Amount        int    `json:"amount"`
+}
+
+// Payment is a recorded payment. Tendered and Change only differ from Amount
+// for cash.
+type Payment struct {
+	ID            int           `json:"id"`
+	TransactionID int           `json:"transaction_id"`
+	Method        PaymentMethod `json:"method"`
+	Amount        int           `json:"amount"`
+	Tendered      int           `json:"tendered"`
+	Change        int           `json:"change"`
+	Reference     string        `json:"reference,omitempty"`
+}
+
+// Checkout sells req.Items. The request carries req.IdempotencyKey, or a
+// random key when it is empty, so retries after a lost response return the
+// transaction already created instead of selling twice. Set the key yourself
+// to make your own retries of the same checkout safe as well.
+// A product without enough stock fails with ErrInsufficientStock.
+func (c *Client) Checkout(ctx context.Context, req CheckoutRequest) (*Transaction, error) {
+	key := req.IdempotencyKey
+	if key == "" {
+		key = rand.Text()
+	}
+	header := http.Header{"Idempotency-Key": {key}}
+
+	var transaction Transaction
+	if err := c.do(ctx, http.MethodPost, "/checkout", header, req, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+func (c *Client) GetTransaction(ctx context.Context, id int) (*Transaction, error) {
+	var transaction Transaction
+	if err := c.do(ctx, http.MethodGet, "/transactions/"+strconv.Itoa(id), nil, nil, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
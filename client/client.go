@@ -0,0 +1,217 @@
+// Package client is a Go client for the Category & Product API.
+//
+//	c, err := client.New("http://localhost:8080", client.Settings{
+//		APIKey: os.Getenv("CATEGORY_API_KEY"),
+//		Retry:  client.DefaultRetry,
+//	})
+//	categories, err := c.ListCategories(ctx)
+//
+// Failed requests return an *Error, which errors.Is matches against the
+// sentinel for its status, such as ErrNotFound.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiPrefix is where the server mounts version 1 of the API.
+const apiPrefix = "/api/v1"
+
+// maxErrorBody caps how much of an error response is kept as its message.
+const maxErrorBody = 64 << 10
+
+// Settings configure a Client. The zero value sends unauthenticated requests
+// with http.DefaultClient and does not retry.
+type Settings struct {
+	// HTTPClient sends the requests; http.DefaultClient when nil.
+	HTTPClient *http.Client
+	// APIKey is sent as X-API-Key.
+	APIKey string
+	// BearerToken is a JWT sent in the Authorization header, when no APIKey
+	// is set.
+	BearerToken string
+	Retry       Retry
+}
+
+// Retry is an exponential backoff: the wait after the nth failed attempt is
+// InitialBackoff doubled n-1 times, capped at MaxBackoff, and randomly
+// shortened by up to half. A Retry-After header from the server takes
+// precedence. Only requests that are safe to repeat are retried: GET, PUT
+// and DELETE, and checkouts, which carry an idempotency key.
+type Retry struct {
+	// Attempts after the first one; 0 disables retries.
+	Retries        int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetry retries three times over about a second.
+var DefaultRetry = Retry{Retries: 3, InitialBackoff: 200 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL  string
+	settings Settings
+}
+
+// New returns a client for the server at baseURL, such as
+// http://localhost:8080.
+func New(baseURL string, s Settings) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("base URL %q is not an http(s) URL", baseURL)
+	}
+	if s.HTTPClient == nil {
+		s.HTTPClient = http.DefaultClient
+	}
+	if s.Retry.Retries < 0 {
+		return nil, errors.New("retries must not be negative")
+	}
+	return &Client{baseURL: strings.TrimSuffix(u.String(), "/") + apiPrefix, settings: s}, nil
+}
+
+// do sends a request to path under the API prefix with body, if not nil, as
+// JSON, and decodes the response into out, if not nil. header is added to
+// the request.
+func (c *Client) do(ctx context.Context, method, path string, header http.Header, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	retry := c.settings.Retry
+	if !repeatable(method, header) {
+		retry.Retries = 0
+	}
+
+	backoff := retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, header, payload)
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return decode(resp, out)
+		}
+
+		if attempt > retry.Retries || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+			return decode(resp, out)
+		}
+		wait := backoff
+		if half := wait / 2; half > 0 {
+			wait -= rand.N(half)
+		}
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				wait = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		if backoff *= 2; retry.MaxBackoff > 0 && backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.settings.APIKey != "" {
+		req.Header.Set("X-API-Key", c.settings.APIKey)
+	} else if c.settings.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.settings.BearerToken)
+	}
+	return c.settings.HTTPClient.Do(req)
+}
+
+// decode turns a 4xx or 5xx response into an *Error and otherwise decodes
+// the body into out. It closes the body.
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &Error{
+			StatusCode: resp.StatusCode,
+			Message:    strings.TrimSpace(string(msg)),
+			Code:       resp.Header.Get("X-Error-Code"),
+			RequestID:  resp.Header.Get("X-Request-ID"),
+		}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s response: %w", resp.Request.URL.Path, err)
+	}
+	return nil
+}
+
+// repeatable reports whether sending the request twice has the same effect
+// as sending it once.
+func repeatable(method string, header http.Header) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return header.Get("Idempotency-Key") != ""
+}
+
+// retryableStatus reports whether a response status is likely transient.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait a Retry-After header asks for, in seconds or
+// as a date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,372 @@
+package client
+
+import (
+	"cateogry-api/internal/handler"
+	"cateogry-api/internal/repository"
+	"cateogry-api/internal/service"
+	"cateogry-api/internal/testdb"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetry = Retry{Retries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// faults answers the first n requests with status instead of passing them
+// on, and counts every request.
+type faults struct {
+	n, status int
+	requests  atomic.Int32
+}
+
+func (f *faults) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(f.requests.Add(1)) <= f.n {
+			http.Error(w, http.StatusText(f.status), f.status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newServer serves v1 under the API prefix the way main does, and returns a
+// client for it.
+func newServer(t *testing.T, v1 http.Handler, s Settings) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(apiPrefix+"/", http.StripPrefix(apiPrefix, v1))
+	srv := httptest.NewServer(handler.RequestID(mux))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func categoryAPI() *http.ServeMux {
+	mux := http.NewServeMux()
+	h := handler.NewCategoryHandler(service.NewCategoryService(repository.NewInMemoryCategoryRepository(), nil))
+	h.RegisterRoutes(mux, nil)
+	return mux
+}
+
+func TestCategories(t *testing.T) {
+	ctx := context.Background()
+	c := newServer(t, categoryAPI(), Settings{})
+
+	categories, err := c.ListCategories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 3 {
+		t.Fatalf("ListCategories returned %d categories, want 3", len(categories))
+	}
+
+	created, err := c.CreateCategory(ctx, Category{Name: "Toys", Description: "Fun stuff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == 0 || created.Name != "Toys" {
+		t.Fatalf("CreateCategory returned %+v", created)
+	}
+
+	updated, err := c.UpdateCategory(ctx, created.ID, Category{Name: "Games", Description: "More fun"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Games" {
+		t.Errorf("UpdateCategory name = %q, want Games", updated.Name)
+	}
+
+	got, err := c.GetCategory(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Games" || got.Description != "More fun" {
+		t.Errorf("GetCategory returned %+v", got)
+	}
+
+	if err := c.DeleteCategory(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetCategory(ctx, created.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetCategory after delete: err = %v, want ErrNotFound", err)
+	}
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err is %T, want *Error", err)
+	}
+	if apiErr.Message != "Category not found" || apiErr.RequestID == "" {
+		t.Errorf("Error = %+v, want the handler's message and a request ID", apiErr)
+	}
+}
+
+func TestRetriesIdempotentRequests(t *testing.T) {
+	f := &faults{n: 2, status: http.StatusServiceUnavailable}
+	c := newServer(t, f.wrap(categoryAPI()), Settings{Retry: fastRetry})
+
+	if _, err := c.GetCategory(context.Background(), 1); err != nil {
+		t.Fatalf("GetCategory: %v", err)
+	}
+	if got := f.requests.Load(); got != 3 {
+		t.Errorf("server saw %d requests, want 3", got)
+	}
+}
+
+func TestGivesUpAfterRetries(t *testing.T) {
+	f := &faults{n: 10, status: http.StatusBadGateway}
+	c := newServer(t, f.wrap(categoryAPI()), Settings{Retry: fastRetry})
+
+	_, err := c.ListCategories(context.Background())
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if got := f.requests.Load(); got != 3 {
+		t.Errorf("server saw %d requests, want 3", got)
+	}
+}
+
+func TestDoesNotRetryCreate(t *testing.T) {
+	f := &faults{n: 1, status: http.StatusServiceUnavailable}
+	c := newServer(t, f.wrap(categoryAPI()), Settings{Retry: fastRetry})
+
+	_, err := c.CreateCategory(context.Background(), Category{Name: "Toys"})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if got := f.requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	f := &faults{n: 1, status: http.StatusNotFound}
+	c := newServer(t, f.wrap(categoryAPI()), Settings{Retry: fastRetry})
+
+	if _, err := c.GetCategory(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if got := f.requests.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	f := &faults{n: 10, status: http.StatusServiceUnavailable}
+	slow := Retry{Retries: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	c := newServer(t, f.wrap(categoryAPI()), Settings{Retry: slow})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.ListCategories(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	authz := service.NewAuthorizer(service.DefaultRolePermissions())
+	auth := service.NewAuthService(repository.NewPostgresAPIKeyRepository(nil), authz, service.AuthSettings{BootstrapAPIKey: "bootstrap-key"})
+	mux := http.NewServeMux()
+	handler.NewCategoryHandler(service.NewCategoryService(repository.NewInMemoryCategoryRepository(), nil)).RegisterRoutes(mux, authz)
+	api := handler.RequireAuth(auth, mux)
+	ctx := context.Background()
+
+	anonymous := newServer(t, api, Settings{})
+	if _, err := anonymous.ListCategories(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("without a key: err = %v, want ErrUnauthorized", err)
+	}
+	wrongKey := newServer(t, api, Settings{APIKey: "wrong"})
+	if _, err := wrongKey.ListCategories(ctx); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("with a wrong key: err = %v, want ErrUnauthorized", err)
+	}
+	admin := newServer(t, api, Settings{APIKey: "bootstrap-key"})
+	if _, err := admin.ListCategories(ctx); err != nil {
+		t.Errorf("with the bootstrap key: %v", err)
+	}
+}
+
+func TestErrorStatuses(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnprocessableEntity, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusServiceUnavailable, ErrUnavailable},
+		{http.StatusInternalServerError, ErrServer},
+	}
+	for _, tt := range tests {
+		f := &faults{n: 1, status: tt.status}
+		c := newServer(t, f.wrap(categoryAPI()), Settings{})
+		_, err := c.ListCategories(context.Background())
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: err = %v, want %v", tt.status, err, tt.want)
+		}
+		if errors.Is(err, ErrInsufficientStock) {
+			t.Errorf("status %d: err = %v matches ErrInsufficientStock", tt.status, err)
+		}
+	}
+}
+
+func TestInsufficientStockCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"insufficient_stock", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.code != "" {
+				w.Header().Set("X-Error-Code", tt.code)
+			}
+			http.Error(w, "insufficient stock: not enough", http.StatusConflict)
+		})
+		_, err := newServer(t, api, Settings{}).ListCategories(context.Background())
+		if got := errors.Is(err, ErrInsufficientStock); got != tt.want {
+			t.Errorf("409 with code %q: errors.Is(err, ErrInsufficientStock) = %v, want %v", tt.code, got, tt.want)
+		}
+		if !errors.Is(err, ErrConflict) {
+			t.Errorf("409 with code %q: err = %v, want ErrConflict", tt.code, err)
+		}
+	}
+}
+
+func TestNewRejectsInvalidURLs(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://example.com", "http://"} {
+		if _, err := New(baseURL, Settings{}); err == nil {
+			t.Errorf("New(%q) succeeded", baseURL)
+		}
+	}
+}
+
+// lostCheckouts passes the first n checkouts on but answers them with 502,
+// as when a proxy drops the response of a request the API did handle.
+type lostCheckouts struct {
+	n         int
+	checkouts atomic.Int32
+}
+
+func (l *lostCheckouts) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/checkout" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if int(l.checkouts.Add(1)) <= l.n {
+			next.ServeHTTP(httptest.NewRecorder(), r)
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// storeAPI serves the product, checkout and report routes over db.
+func storeAPI(db *sql.DB) *http.ServeMux {
+	mux := http.NewServeMux()
+	productRepo := repository.NewPostgresProductRepository(db)
+	handler.NewProductHandler(service.NewProductService(productRepo, nil)).RegisterRoutes(mux, nil)
+	handler.NewCategoryHandler(service.NewCategoryService(repository.NewPostgresCategoryRepository(db), nil)).RegisterRoutes(mux, nil)
+	transactionSvc := service.NewTransactionService(repository.NewPostgresTransactionRepository(db), repository.NewPostgresPromotionRepository(db),
+		repository.NewPostgresTaxRateRepository(db), service.TaxSettings{}, nil)
+	handler.NewTransactionHandler(transactionSvc).RegisterRoutes(mux, nil)
+	return mux
+}
+
+func TestCheckoutAndReportsPostgres(t *testing.T) {
+	db := testdb.Open(t)
+	lost := &lostCheckouts{n: 1}
+	c := newServer(t, lost.wrap(storeAPI(db)), Settings{Retry: fastRetry})
+	ctx := context.Background()
+
+	category, err := c.CreateCategory(ctx, Category{Name: "Books"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	product, err := c.CreateProduct(ctx, Product{Name: "Clean Code", Price: 450000, CostPrice: 300000, Stock: 10, CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if products, err := c.ListProducts(ctx, "clean"); err != nil || len(products) != 1 {
+		t.Fatalf("ListProducts = %v, %v; want the new product", products, err)
+	}
+	if products, err := c.ListCategoryProducts(ctx, category.ID); err != nil || len(products) != 1 {
+		t.Fatalf("ListCategoryProducts = %v, %v; want the new product", products, err)
+	}
+	if _, err := c.ListCategoryProducts(ctx, category.ID+1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ListCategoryProducts of a missing category: err = %v, want %v", err, ErrNotFound)
+	}
+
+	transaction, err := c.Checkout(ctx, CheckoutRequest{
+		Items:    []CheckoutItem{{ProductID: product.ID, Quantity: 2}},
+		Payments: []CheckoutPayment{{Method: PaymentCash, Amount: 900000}},
+	})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if got := lost.checkouts.Load(); got != 2 {
+		t.Errorf("server saw %d checkout requests, want 2", got)
+	}
+	if transaction.TotalAmount != 900000 {
+		t.Errorf("TotalAmount = %d, want 900000", transaction.TotalAmount)
+	}
+
+	// The retry must have returned the first sale instead of selling again
+	after, err := c.GetProduct(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Stock != 8 {
+		t.Errorf("stock after checkout = %d, want 8", after.Stock)
+	}
+	got, err := c.GetTransaction(ctx, transaction.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Details) != 1 || got.Details[0].Quantity != 2 {
+		t.Errorf("GetTransaction details = %+v", got.Details)
+	}
+
+	_, err = c.Checkout(ctx, CheckoutRequest{Items: []CheckoutItem{{ProductID: product.ID, Quantity: 100}}})
+	if !errors.Is(err, ErrInsufficientStock) || !errors.Is(err, ErrConflict) {
+		t.Errorf("checkout over stock: err = %v, want ErrInsufficientStock", err)
+	}
+
+	report, err := c.TodayReport(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalTransactions != 1 || report.TotalRevenue != 900000 {
+		t.Errorf("TodayReport = %d transactions, %d revenue; want 1, 900000", report.TotalTransactions, report.TotalRevenue)
+	}
+	now := time.Now()
+	report, err = c.Report(ctx, now, now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalTransactions != 1 {
+		t.Errorf("Report = %d transactions, want 1", report.TotalTransactions)
+	}
+
+	if err := c.DeleteProduct(ctx, product.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetProduct(ctx, product.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetProduct after delete: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinels matched by errors.Is against an *Error with the corresponding
+// status.
+var (
+	// ErrBadRequest is a 400 or 422: the request was invalid.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized is a 401: credentials are missing or invalid.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is a 403: the credentials lack a permission.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound is a 404.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is a 409: the request conflicts with the current state.
+	ErrConflict = errors.New("conflict")
+	// ErrInsufficientStock is the 409 a checkout or reservation gets when a
+	// product does not have enough stock. Such errors match ErrConflict too.
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrRateLimited is a 429.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnavailable is a 502, 503 or 504: the server, or the database
+	// behind it, is not reachable for now.
+	ErrUnavailable = errors.New("unavailable")
+	// ErrServer is any other 5xx.
+	ErrServer = errors.New("server error")
+)
+
+// errorCodeInsufficientStock is the X-Error-Code of a 409 for a product
+// without enough stock.
+const errorCodeInsufficientStock = "insufficient_stock"
+
+// Error is a response with a 4xx or 5xx status.
+type Error struct {
+	StatusCode int
+	// Message is the response body, which the API fills with a short
+	// description of the problem.
+	Message string
+	// Code is the X-Error-Code header, which the API sets for errors callers
+	// may want to tell apart, such as "insufficient_stock".
+	Code string
+	// RequestID identifies the request in the server logs.
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is reports whether target is the sentinel for the status of e, or
+// ErrInsufficientStock for a 409 with that Code.
+func (e *Error) Is(target error) bool {
+	if target == ErrInsufficientStock {
+		return e.StatusCode == http.StatusConflict && e.Code == errorCodeInsufficientStock
+	}
+	return target == statusError(e.StatusCode)
+}
+
+func statusError(code int) error {
+	switch {
+	case code == http.StatusBadRequest, code == http.StatusUnprocessableEntity:
+		return ErrBadRequest
+	case code == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case code == http.StatusForbidden:
+		return ErrForbidden
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code == http.StatusConflict:
+		return ErrConflict
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code == http.StatusBadGateway, code == http.StatusServiceUnavailable, code == http.StatusGatewayTimeout:
+		return ErrUnavailable
+	case code >= 500:
+		return ErrServer
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Product is a product with its stock. Stock is the total over all
+// locations, and AvailableStock is what active reservations leave of it.
+type Product struct {
+	ID             int            `json:"id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	Price          int            `json:"price"`
+	CostPrice      int            `json:"cost_price"`
+	Stock          int            `json:"stock"`
+	AvailableStock int            `json:"available_stock"`
+	Locations      []ProductStock `json:"locations,omitempty"`
+	CategoryID     int            `json:"category_id"`
+	CategoryName   string         `json:"category_name,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// ProductStock is the stock of a product at one location.
+type ProductStock struct {
+	LocationID   int    `json:"location_id"`
+	LocationName string `json:"location_name"`
+	Quantity     int    `json:"quantity"`
+}
+
+// ListProducts returns the products whose name contains name, or every
+// product when name is empty.
+func (c *Client) ListProducts(ctx context.Context, name string) ([]Product, error) {
+	path := "/products"
+	if name != "" {
+		path += "?" + url.Values{"name": {name}}.Encode()
+	}
+	var products []Product
+	err := c.do(ctx, http.MethodGet, path, nil, nil, &products)
+	return products, err
+}
+
+// ListCategoryProducts returns the products of the category with id.
+func (c *Client) ListCategoryProducts(ctx context.Context, id int) ([]Product, error) {
+	var products []Product
+	err := c.do(ctx, http.MethodGet, "/categories/"+strconv.Itoa(id)+"/products", nil, nil, &products)
+	return products, err
+}
+
+func (c *Client) GetProduct(ctx context.Context, id int) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodGet, "/products/"+strconv.Itoa(id), nil, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// CreateProduct creates a product, putting its Stock at the default
+// location. It is not retried.
+func (c *Client) CreateProduct(ctx context.Context, product Product) (*Product, error) {
+	var created Product
+	if err := c.do(ctx, http.MethodPost, "/products", nil, product, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) UpdateProduct(ctx context.Context, id int, product Product) (*Product, error) {
+	var updated Product
+	if err := c.do(ctx, http.MethodPut, "/products/"+strconv.Itoa(id), nil, product, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (c *Client) DeleteProduct(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/products/"+strconv.Itoa(id), nil, nil, nil)
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Report sums up the sales of a period. NetSales exclude discounts and tax,
+// and TotalCOGS is the cost of the units sold.
+type Report struct {
+	TotalRevenue       int                    `json:"total_revenue"`
+	TotalTransactions  int                    `json:"total_transaksi"`
+	TotalDiscount      int                    `json:"total_diskon"`
+	TotalTax           int                    `json:"total_pajak"`
+	BestSellingProduct BestSellingProduct     `json:"produk_terlaris"`
+	Promotions         []PromotionUsage       `json:"promo"`
+	Taxes              []TaxSummary           `json:"pajak"`
+	PaymentMethods     []PaymentMethodSummary `json:"metode_pembayaran"`
+	NetSales           int                    `json:"penjualan_bersih"`
+	TotalCOGS          int                    `json:"total_hpp"`
+	GrossProfit        int                    `json:"laba_kotor"`
+	MarginPercent      float64                `json:"margin_persen"`
+	ProductMargins     []ProductMargin        `json:"margin_produk"`
+	CategoryMargins    []CategoryMargin       `json:"margin_kategori"`
+}
+
+type BestSellingProduct struct {
+	Name    string `json:"nama"`
+	QtySold int    `json:"qty_terjual"`
+}
+
+type PromotionUsage struct {
+	PromotionID   int    `json:"promotion_id"`
+	Name          string `json:"nama"`
+	Code          string `json:"kode,omitempty"`
+	TimesApplied  int    `json:"digunakan"`
+	TotalDiscount int    `json:"total_diskon"`
+}
+
+// TaxSummary is the tax collected at one rate, in basis points.
+type TaxSummary struct {
+	Rate          int `json:"tarif"`
+	TaxableAmount int `json:"dasar_pengenaan"`
+	TaxAmount     int `json:"pajak"`
+}
+
+type PaymentMethodSummary struct {
+	Method       PaymentMethod `json:"metode"`
+	Transactions int           `json:"jumlah_transaksi"`
+	Amount       int           `json:"total"`
+}
+
+type ProductMargin struct {
+	ProductID     int     `json:"product_id"`
+	Name          string  `json:"nama"`
+	QtySold       int     `json:"qty_terjual"`
+	NetSales      int     `json:"penjualan_bersih"`
+	COGS          int     `json:"hpp"`
+	GrossProfit   int     `json:"laba_kotor"`
+	MarginPercent float64 `json:"margin_persen"`
+}
+
+type CategoryMargin struct {
+	CategoryID    int     `json:"category_id"`
+	Name          string  `json:"nama"`
+	QtySold       int     `json:"qty_terjual"`
+	NetSales      int     `json:"penjualan_bersih"`
+	COGS          int     `json:"hpp"`
+	GrossProfit   int     `json:"laba_kotor"`
+	MarginPercent float64 `json:"margin_persen"`
+}
+
+// TodayReport returns the sales report of the current day in the server's
+// time zone.
+func (c *Client) TodayReport(ctx context.Context) (*Report, error) {
+	var report Report
+	if err := c.do(ctx, http.MethodGet, "/report/hari-ini", nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Report returns the sales report from the day of from up to, but not
+// including, the day of to.
+func (c *Client) Report(ctx context.Context, from, to time.Time) (*Report, error) {
+	query := url.Values{
+		"start_date": {from.Format(time.DateOnly)},
+		"end_date":   {to.Format(time.DateOnly)},
+	}
+	var report Report
+	if err := c.do(ctx, http.MethodGet, "/report?"+query.Encode(), nil, nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"cateogry-api/database"
+	"cateogry-api/internal/config"
+	"cateogry-api/internal/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func dbSettings(cfg config.DB) database.Settings {
+	return database.Settings{
+		QueryTimeout:    cfg.QueryTimeout,
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+		ConnMaxIdleTime: cfg.ConnMaxIdleTime,
+		Retry: database.Retry{
+			Retries:        cfg.ConnectRetries,
+			InitialBackoff: cfg.RetryBackoff,
+			MaxBackoff:     cfg.RetryMaxBackoff,
+		},
+		SSLMode:        cfg.SSLMode,
+		SSLRootCert:    cfg.SSLRootCert,
+		PreferIPv4:     cfg.PreferIPv4,
+		SimpleProtocol: cfg.SimpleProtocol,
+	}
+}
+
+// connect opens the database for a one-off command, which unlike serve has
+// nothing to do until it answers.
+func connect(ctx context.Context, cfg config.DB) (*sql.DB, error) {
+	db, err := database.InitDB(ctx, cfg.Conn, dbSettings(cfg))
+	if err != nil {
+		if db != nil {
+			db.Close()
+		}
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, cfg config.Config, args []string) error {
+	fs := newFlagSet("migrate", "up | down [-steps n] | status")
+	if len(args) == 0 {
+		return usageError(fs, "missing migrate direction")
+	}
+	direction, args := args[0], args[1:]
+	steps := 1
+	if direction == "down" {
+		fs.IntVar(&steps, "steps", 1, "number of migrations to revert, newest first")
+	}
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	switch direction {
+	case "up", "down", "status":
+	default:
+		return usageError(fs, "unknown migrate direction %q", direction)
+	}
+	if steps < 1 {
+		return usageError(fs, "-steps must be at least 1")
+	}
+
+	db, err := connect(ctx, cfg.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch direction {
+	case "up":
+		return database.Migrate(ctx, db)
+	case "down":
+		return database.MigrateDown(ctx, db, steps)
+	}
+
+	states, err := database.MigrationStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED")
+	for _, s := range states {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return tw.Flush()
+}
+
+// cleanupStep is one purge of the cleanup routine. job names it in the
+// metrics and the health report.
+type cleanupStep struct {
+	job string
+	run func(context.Context) error
+}
+
+// cleanupSteps purges categories and products deleted more than olderThan
+// ago, then expired carts.
+func cleanupSteps(db *sql.DB, olderThan time.Duration) []cleanupStep {
+	categories := repository.NewPostgresCategoryRepository(db)
+	products := repository.NewPostgresProductRepository(db)
+	carts := repository.NewPostgresCartRepository(db)
+	return []cleanupStep{
+		{"cleanup_categories", func(ctx context.Context) error { return categories.CleanUpOldDeleted(ctx, olderThan) }},
+		{"cleanup_products", func(ctx context.Context) error { return products.CleanUpOldDeleted(ctx, olderThan) }},
+		{"cleanup_carts", carts.CleanUpExpired},
+	}
+}
+
+func cleanup(ctx context.Context, cfg config.Config, args []string) error {
+	fs := newFlagSet("cleanup", "[-older-than duration]")
+	olderThan := fs.Duration("older-than", cfg.Cleanup.Retention, "purge records soft-deleted longer ago than this; defaults to cleanup.retention")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *olderThan <= 0 {
+		return usageError(fs, "-older-than must be a positive duration")
+	}
+
+	db, err := connect(ctx, cfg.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, step := range cleanupSteps(db, *olderThan) {
+		if err := step.run(ctx); err != nil {
+			return fmt.Errorf("%s: %w", step.job, err)
+		}
+		fmt.Println(step.job, "done")
+	}
+	return nil
+}
+
+// checkDB connects once, without the configured retries, and reports the
+// server version and how far the schema is migrated.
+func checkDB(ctx context.Context, cfg config.Config, args []string) error {
+	fs := newFlagSet("check-db", "")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	fmt.Println("Connecting to", database.Redact(cfg.DB.Conn))
+	cfg.DB.ConnectRetries = 0
+	db, err := connect(ctx, cfg.DB)
+	if err != nil {
+		return fmt.Errorf("database connection failed: %w", err)
+	}
+	defer db.Close()
+
+	var version string
+	if err := db.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+		return err
+	}
+	fmt.Println("Connected to PostgreSQL", version)
+
+	states, err := database.MigrationStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, s := range states {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	schema, err := database.SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("schema at version %d, %d migrations pending; run migrate up", schema, pending)
+	}
+	fmt.Printf("Schema up to date at version %d\n", schema)
+	return nil
+}
@@ -1,63 +1,110 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-	"log"
-	"net"
-	"net/url"
+	"log/slog"
+	"math/rand/v2"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/XSAM/otelsql"
+	"github.com/jackc/pgx/v5/stdlib"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
 )
 
-func InitDB(connectionString string) (*sql.DB, error) {
-	// Attempt to resolve hostname to IPv4 to avoid IPv6 issues
-	resolvedConnString := connectionString
-	u, err := url.Parse(connectionString)
-	if err == nil && u.Host != "" {
-		host := u.Hostname()
-		port := u.Port()
-
-		// Only resolve if it's not already an IP
-		if net.ParseIP(host) == nil {
-			ips, err := net.LookupIP(host)
-			if err == nil {
-				var ipv4 net.IP
-				for _, ip := range ips {
-					if ip.To4() != nil {
-						ipv4 = ip
-						break
-					}
-				}
-				if ipv4 != nil {
-					log.Printf("Resolved database host %s to %s", host, ipv4.String())
-					if port != "" {
-						u.Host = net.JoinHostPort(ipv4.String(), port)
-					} else {
-						u.Host = ipv4.String()
-					}
-					resolvedConnString = u.String()
-				}
-			}
-		}
-	}
+// pingTimeout bounds a single connection attempt, so an unreachable host
+// costs one attempt rather than hanging startup.
+const pingTimeout = 5 * time.Second
+
+// Settings configures the connection pool and how long startup waits for
+// the database.
+type Settings struct {
+	// QueryTimeout, when positive, becomes the server-side statement_timeout
+	// of every connection, so no single statement can run longer than that.
+	QueryTimeout    time.Duration
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	Retry           Retry
+
+	// SSLMode and SSLRootCert override the sslmode and sslrootcert given in
+	// the connection string.
+	SSLMode     string
+	SSLRootCert string
+	// PreferIPv4 connects to a host's IPv4 addresses when it has any, for
+	// networks without IPv6 routes.
+	PreferIPv4 bool
+	// SimpleProtocol sends queries without server-side prepared statements,
+	// as transaction-pooling PgBouncer and similar proxies require.
+	SimpleProtocol bool
+}
 
-	// Open database
-	db, err := sql.Open("pgx", resolvedConnString+"&prefer_simple_protocol=true")
+// Retry is an exponential backoff: the wait after the nth failed attempt is
+// InitialBackoff doubled n-1 times, capped at MaxBackoff, and randomly
+// shortened by up to half so that restarted instances do not retry in step.
+type Retry struct {
+	// Attempts after the first one; negative retries until the context ends.
+	Retries        int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// InitDB opens the connection pool and waits for the database to answer,
+// retrying per s.Retry. When it never does, the pool is returned anyway
+// along with the last error: database/sql dials lazily, so the pool starts
+// working as soon as the database is reachable, and broken connections are
+// replaced the same way later on. A nil pool means connectionString or the
+// TLS settings are invalid.
+func InitDB(ctx context.Context, connectionString string, s Settings) (*sql.DB, error) {
+	config, err := ParseConfig(connectionString, s)
 	if err != nil {
 		return nil, err
 	}
+	slog.Info("Connecting to database", "dsn", Redact(connectionString),
+		"prefer_ipv4", s.PreferIPv4, "simple_protocol", s.SimpleProtocol)
 
-	// Test connection
-	err = db.Ping()
-	if err != nil {
-		log.Println("Database connection failed (will retry):", err)
-		return db, err // Return db object anyway so we can keep trying
+	// Every statement gets a span under the caller's context
+	db := otelsql.OpenDB(stdlib.GetConnector(*config), otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL))
+	db.SetMaxOpenConns(s.MaxOpenConns)
+	db.SetMaxIdleConns(s.MaxIdleConns)
+	db.SetConnMaxLifetime(s.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(s.ConnMaxIdleTime)
+
+	if err := WaitForDB(ctx, db, s.Retry); err != nil {
+		return db, err
 	}
+	slog.Info("Database connected successfully")
+	return db, nil
+}
 
-	// Set connection pool settings (optional but recommended)
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
+// WaitForDB pings db until it answers, backing off between attempts, and
+// returns the last error once the retries run out or ctx ends.
+func WaitForDB(ctx context.Context, db *sql.DB, r Retry) error {
+	backoff := r.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		err := db.PingContext(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if r.Retries >= 0 && attempt > r.Retries {
+			return err
+		}
 
-	log.Println("Database connected successfully")
-	return db, nil
+		wait := backoff
+		if half := wait / 2; half > 0 {
+			wait -= rand.N(half)
+		}
+		slog.Warn("Database connection failed, retrying", "attempt", attempt, "retry_in", wait.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		if backoff *= 2; r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+		}
+	}
 }
@@ -0,0 +1,645 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Migration is a single, ordered schema change. Versions must be unique and
+// increasing; Down reverts exactly what Up applied.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: `
+			CREATE TABLE IF NOT EXISTS categories (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				deleted_at TIMESTAMP NULL
+			);
+			CREATE TABLE IF NOT EXISTS products (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				price INT NOT NULL DEFAULT 0,
+				stock INT NOT NULL DEFAULT 0,
+				category_id INT NOT NULL REFERENCES categories(id),
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				deleted_at TIMESTAMP NULL
+			);
+			CREATE TABLE IF NOT EXISTS transactions (
+				id SERIAL PRIMARY KEY,
+				total_amount INT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE TABLE IF NOT EXISTS transaction_details (
+				id SERIAL PRIMARY KEY,
+				transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+				product_id INT REFERENCES products(id),
+				quantity INT NOT NULL,
+				subtotal INT NOT NULL
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS transaction_details;
+			DROP TABLE IF EXISTS transactions;
+			DROP TABLE IF EXISTS products;
+			DROP TABLE IF EXISTS categories;
+		`,
+	},
+	{
+		// Snapshot what was actually sold so transactions and reports survive
+		// products being renamed, repriced or purged by CleanUpOldDeleted.
+		Version: 2,
+		Name:    "transaction_detail_snapshots",
+		Up: `
+			ALTER TABLE transaction_details
+				ADD COLUMN IF NOT EXISTS unit_price INT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS product_name VARCHAR(255) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS category_id INT NULL,
+				ADD COLUMN IF NOT EXISTS category_name VARCHAR(255) NOT NULL DEFAULT '';
+			UPDATE transaction_details td
+			SET unit_price = CASE WHEN td.quantity > 0 THEN td.subtotal / td.quantity ELSE 0 END,
+			    product_name = p.name,
+			    category_id = p.category_id,
+			    category_name = COALESCE(c.name, '')
+			FROM products p
+			LEFT JOIN categories c ON c.id = p.category_id
+			WHERE td.product_id = p.id AND td.product_name = '';
+			ALTER TABLE transaction_details ALTER COLUMN product_id DROP NOT NULL;
+			ALTER TABLE transaction_details DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey;
+			ALTER TABLE transaction_details ADD CONSTRAINT transaction_details_product_id_fkey
+				FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+		`,
+		Down: `
+			ALTER TABLE transaction_details DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey;
+			ALTER TABLE transaction_details ADD CONSTRAINT transaction_details_product_id_fkey
+				FOREIGN KEY (product_id) REFERENCES products(id);
+			ALTER TABLE transaction_details
+				DROP COLUMN IF EXISTS category_name,
+				DROP COLUMN IF EXISTS category_id,
+				DROP COLUMN IF EXISTS product_name,
+				DROP COLUMN IF EXISTS unit_price;
+		`,
+	},
+	{
+		Version: 3,
+		Name:    "promotions",
+		Up: `
+			CREATE TABLE IF NOT EXISTS promotions (
+				id SERIAL PRIMARY KEY,
+				code VARCHAR(64) NOT NULL DEFAULT '',
+				name VARCHAR(255) NOT NULL,
+				type VARCHAR(32) NOT NULL,
+				value INT NOT NULL DEFAULT 0,
+				product_id INT NULL,
+				category_id INT NULL,
+				buy_quantity INT NOT NULL DEFAULT 0,
+				get_quantity INT NOT NULL DEFAULT 0,
+				stackable BOOLEAN NOT NULL DEFAULT FALSE,
+				priority INT NOT NULL DEFAULT 0,
+				active BOOLEAN NOT NULL DEFAULT TRUE,
+				starts_at TIMESTAMP NULL,
+				ends_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				deleted_at TIMESTAMP NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS promotions_code_key ON promotions (UPPER(code))
+				WHERE code <> '' AND deleted_at IS NULL;
+			ALTER TABLE transactions
+				ADD COLUMN IF NOT EXISTS subtotal INT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+			UPDATE transactions SET subtotal = total_amount WHERE subtotal = 0;
+			ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+			CREATE TABLE IF NOT EXISTS transaction_discounts (
+				id SERIAL PRIMARY KEY,
+				transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+				promotion_id INT NULL REFERENCES promotions(id) ON DELETE SET NULL,
+				code VARCHAR(64) NOT NULL DEFAULT '',
+				name VARCHAR(255) NOT NULL,
+				type VARCHAR(32) NOT NULL,
+				product_id INT NULL,
+				amount INT NOT NULL
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS transaction_discounts;
+			ALTER TABLE transaction_details DROP COLUMN IF EXISTS discount_amount;
+			ALTER TABLE transactions
+				DROP COLUMN IF EXISTS discount_amount,
+				DROP COLUMN IF EXISTS subtotal;
+			DROP TABLE IF EXISTS promotions;
+		`,
+	},
+	{
+		Version: 4,
+		Name:    "taxes",
+		Up: `
+			CREATE TABLE IF NOT EXISTS tax_rates (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				category_id INT NULL REFERENCES categories(id) ON DELETE CASCADE,
+				rate INT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS tax_rates_category_key ON tax_rates (COALESCE(category_id, 0));
+			ALTER TABLE transactions
+				ADD COLUMN IF NOT EXISTS tax_amount INT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+			ALTER TABLE transaction_details
+				ADD COLUMN IF NOT EXISTS tax_rate INT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS tax_amount INT NOT NULL DEFAULT 0;
+		`,
+		Down: `
+			ALTER TABLE transaction_details
+				DROP COLUMN IF EXISTS tax_amount,
+				DROP COLUMN IF EXISTS tax_rate;
+			ALTER TABLE transactions
+				DROP COLUMN IF EXISTS tax_inclusive,
+				DROP COLUMN IF EXISTS tax_amount;
+			DROP TABLE IF EXISTS tax_rates;
+		`,
+	},
+	{
+		Version: 5,
+		Name:    "transaction_payments",
+		Up: `
+			CREATE TABLE IF NOT EXISTS transaction_payments (
+				id SERIAL PRIMARY KEY,
+				transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+				method VARCHAR(32) NOT NULL,
+				amount INT NOT NULL,
+				tendered INT NOT NULL,
+				change_amount INT NOT NULL DEFAULT 0,
+				reference VARCHAR(255) NOT NULL DEFAULT ''
+			);
+			CREATE INDEX IF NOT EXISTS transaction_payments_transaction_id_idx ON transaction_payments (transaction_id);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS transaction_payments;
+		`,
+	},
+	{
+		Version: 6,
+		Name:    "carts",
+		Up: `
+			CREATE TABLE IF NOT EXISTS carts (
+				id SERIAL PRIMARY KEY,
+				status VARCHAR(32) NOT NULL DEFAULT 'open',
+				transaction_id INT NULL REFERENCES transactions(id) ON DELETE SET NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE TABLE IF NOT EXISTS cart_items (
+				cart_id INT NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+				product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+				quantity INT NOT NULL,
+				PRIMARY KEY (cart_id, product_id)
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS cart_items;
+			DROP TABLE IF EXISTS carts;
+		`,
+	},
+	{
+		Version: 7,
+		Name:    "stock_reservations",
+		Up: `
+			CREATE TABLE IF NOT EXISTS stock_reservations (
+				id SERIAL PRIMARY KEY,
+				status VARCHAR(32) NOT NULL DEFAULT 'active',
+				transaction_id INT NULL REFERENCES transactions(id) ON DELETE SET NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS stock_reservations_active_idx ON stock_reservations (expires_at) WHERE status = 'active';
+			CREATE TABLE IF NOT EXISTS stock_reservation_items (
+				reservation_id INT NOT NULL REFERENCES stock_reservations(id) ON DELETE CASCADE,
+				product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+				quantity INT NOT NULL,
+				PRIMARY KEY (reservation_id, product_id)
+			);
+			CREATE INDEX IF NOT EXISTS stock_reservation_items_product_id_idx ON stock_reservation_items (product_id);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS stock_reservation_items;
+			DROP TABLE IF EXISTS stock_reservations;
+		`,
+	},
+	{
+		// Existing stock moves to a default location so products.stock stays
+		// the total over all locations.
+		Version: 8,
+		Name:    "locations",
+		Up: `
+			CREATE TABLE IF NOT EXISTS locations (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				type VARCHAR(32) NOT NULL DEFAULT 'store',
+				address TEXT NOT NULL DEFAULT '',
+				is_default BOOLEAN NOT NULL DEFAULT FALSE,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				deleted_at TIMESTAMP NULL
+			);
+			CREATE UNIQUE INDEX IF NOT EXISTS locations_default_key ON locations (is_default) WHERE is_default;
+			INSERT INTO locations (name, type, is_default)
+			SELECT 'Main Store', 'store', TRUE
+			WHERE NOT EXISTS (SELECT 1 FROM locations WHERE is_default);
+			CREATE TABLE IF NOT EXISTS product_stocks (
+				product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+				location_id INT NOT NULL REFERENCES locations(id),
+				quantity INT NOT NULL DEFAULT 0,
+				PRIMARY KEY (product_id, location_id)
+			);
+			INSERT INTO product_stocks (product_id, location_id, quantity)
+			SELECT p.id, l.id, p.stock FROM products p CROSS JOIN locations l
+			WHERE l.is_default AND p.stock <> 0
+			ON CONFLICT DO NOTHING;
+			CREATE TABLE IF NOT EXISTS stock_transfers (
+				id SERIAL PRIMARY KEY,
+				product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+				from_location_id INT NOT NULL REFERENCES locations(id),
+				to_location_id INT NOT NULL REFERENCES locations(id),
+				quantity INT NOT NULL,
+				note TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			ALTER TABLE transactions ADD COLUMN IF NOT EXISTS location_id INT NULL REFERENCES locations(id);
+			UPDATE transactions SET location_id = (SELECT id FROM locations WHERE is_default) WHERE location_id IS NULL;
+		`,
+		Down: `
+			ALTER TABLE transactions DROP COLUMN IF EXISTS location_id;
+			DROP TABLE IF EXISTS stock_transfers;
+			DROP TABLE IF EXISTS product_stocks;
+			DROP TABLE IF EXISTS locations;
+		`,
+	},
+	{
+		Version: 9,
+		Name:    "suppliers_purchase_orders",
+		Up: `
+			CREATE TABLE IF NOT EXISTS suppliers (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				contact_name VARCHAR(255) NOT NULL DEFAULT '',
+				phone VARCHAR(64) NOT NULL DEFAULT '',
+				email VARCHAR(255) NOT NULL DEFAULT '',
+				address TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				deleted_at TIMESTAMP NULL
+			);
+			CREATE TABLE IF NOT EXISTS purchase_orders (
+				id SERIAL PRIMARY KEY,
+				supplier_id INT NOT NULL REFERENCES suppliers(id),
+				location_id INT NOT NULL REFERENCES locations(id),
+				status VARCHAR(32) NOT NULL DEFAULT 'draft',
+				note TEXT NOT NULL DEFAULT '',
+				ordered_at TIMESTAMP NULL,
+				received_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE TABLE IF NOT EXISTS purchase_order_items (
+				id SERIAL PRIMARY KEY,
+				purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+				product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+				quantity_ordered INT NOT NULL,
+				quantity_received INT NOT NULL DEFAULT 0,
+				unit_cost INT NOT NULL,
+				UNIQUE (purchase_order_id, product_id)
+			);
+			CREATE TABLE IF NOT EXISTS stock_receipts (
+				id SERIAL PRIMARY KEY,
+				purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+				product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+				location_id INT NOT NULL REFERENCES locations(id),
+				quantity INT NOT NULL,
+				unit_cost INT NOT NULL,
+				received_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS stock_receipts;
+			DROP TABLE IF EXISTS purchase_order_items;
+			DROP TABLE IF EXISTS purchase_orders;
+			DROP TABLE IF EXISTS suppliers;
+		`,
+	},
+	{
+		Version: 10,
+		Name:    "cost_price",
+		Up: `
+			ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price INT NOT NULL DEFAULT 0;
+			ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_cost INT NOT NULL DEFAULT 0;
+		`,
+		Down: `
+			ALTER TABLE transaction_details DROP COLUMN IF EXISTS unit_cost;
+			ALTER TABLE products DROP COLUMN IF EXISTS cost_price;
+		`,
+	},
+	{
+		Version: 11,
+		Name:    "api_keys",
+		Up: `
+			CREATE TABLE IF NOT EXISTS api_keys (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				prefix VARCHAR(16) NOT NULL,
+				key_hash CHAR(64) NOT NULL UNIQUE,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				last_used_at TIMESTAMP NULL,
+				revoked_at TIMESTAMP NULL
+			);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS api_keys;
+		`,
+	},
+	{
+		Version: 12,
+		Name:    "api_key_roles",
+		Up: `
+			-- Keys created before roles existed had full access
+			ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'admin';
+			ALTER TABLE api_keys ALTER COLUMN role DROP DEFAULT;
+		`,
+		Down: `
+			ALTER TABLE api_keys DROP COLUMN IF EXISTS role;
+		`,
+	},
+	{
+		Version: 13,
+		Name:    "audit_log",
+		Up: `
+			CREATE TABLE IF NOT EXISTS audit_log (
+				id SERIAL PRIMARY KEY,
+				actor VARCHAR(255) NOT NULL,
+				action VARCHAR(16) NOT NULL,
+				entity_type VARCHAR(64) NOT NULL,
+				entity_id INT NOT NULL,
+				before JSONB NULL,
+				after JSONB NULL,
+				changes JSONB NOT NULL,
+				request_id VARCHAR(64) NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id);
+			CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+		`,
+		Down: `
+			DROP TABLE IF EXISTS audit_log;
+		`,
+	},
+	{
+		Version: 14,
+		Name:    "checkout_idempotency_keys",
+		Up: `
+			ALTER TABLE transactions ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255) NULL;
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key ON transactions (idempotency_key) WHERE idempotency_key IS NOT NULL;
+		`,
+		Down: `
+			DROP INDEX IF EXISTS idx_transactions_idempotency_key;
+			ALTER TABLE transactions DROP COLUMN IF EXISTS idempotency_key;
+		`,
+	},
+	{
+		Version: 15,
+		Name:    "reservation_owners",
+		Up: `
+			ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS created_by VARCHAR(255) NOT NULL DEFAULT '';
+		`,
+		Down: `
+			ALTER TABLE stock_reservations DROP COLUMN IF EXISTS created_by;
+		`,
+	},
+	{
+		Version: 16,
+		Name:    "keep_product_stock_history",
+		Up: `
+			-- Purging a product must not erase its transfers and purchases
+			ALTER TABLE stock_transfers DROP CONSTRAINT IF EXISTS stock_transfers_product_id_fkey,
+				ADD CONSTRAINT stock_transfers_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+			ALTER TABLE purchase_order_items DROP CONSTRAINT IF EXISTS purchase_order_items_product_id_fkey,
+				ADD CONSTRAINT purchase_order_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+			ALTER TABLE stock_receipts DROP CONSTRAINT IF EXISTS stock_receipts_product_id_fkey,
+				ADD CONSTRAINT stock_receipts_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
+		`,
+		Down: `
+			ALTER TABLE stock_receipts DROP CONSTRAINT IF EXISTS stock_receipts_product_id_fkey,
+				ADD CONSTRAINT stock_receipts_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+			ALTER TABLE purchase_order_items DROP CONSTRAINT IF EXISTS purchase_order_items_product_id_fkey,
+				ADD CONSTRAINT purchase_order_items_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+			ALTER TABLE stock_transfers DROP CONSTRAINT IF EXISTS stock_transfers_product_id_fkey,
+				ADD CONSTRAINT stock_transfers_product_id_fkey FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+		`,
+	},
+	{
+		Version: 17,
+		Name:    "idempotency_key_owners",
+		Up: `
+			ALTER TABLE transactions ADD COLUMN IF NOT EXISTS idempotency_caller VARCHAR(255) NOT NULL DEFAULT '',
+				ADD COLUMN IF NOT EXISTS idempotency_hash VARCHAR(64) NOT NULL DEFAULT '';
+		`,
+		Down: `
+			ALTER TABLE transactions DROP COLUMN IF EXISTS idempotency_hash,
+				DROP COLUMN IF EXISTS idempotency_caller;
+		`,
+	},
+}
+
+// LatestVersion is the version of the newest known migration.
+func LatestVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the newest migration applied to db, or 0 when none
+// has been.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+func createMigrationsTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	return err
+}
+
+// Migrate applies every migration newer than the version recorded in
+// schema_migrations, each inside its own transaction. The per-query statement
+// timeout does not apply to migrations.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if err := createMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+
+	current, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		slog.Info("Applied migration", "version", m.Version, "name", m.Name)
+	}
+	return nil
+}
+
+// MigrateDown reverts the newest steps applied migrations, newest first, each
+// inside its own transaction.
+func MigrateDown(ctx context.Context, db *sql.DB, steps int) error {
+	if err := createMigrationsTable(ctx, db); err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations ORDER BY version DESC LIMIT $1", steps)
+	if err != nil {
+		return err
+	}
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return err
+		}
+		versions = append(versions, version)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		m, ok := findMigration(version)
+		if !ok {
+			return fmt.Errorf("migration %d is applied but unknown to this build", version)
+		}
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		slog.Info("Reverted migration", "version", m.Version, "name", m.Name)
+	}
+	return nil
+}
+
+// MigrationState is a migration and when it was applied, if it has been.
+type MigrationState struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// MigrationStatus lists every known migration in order, followed by any
+// applied migration this build does not know about.
+func MigrationStatus(ctx context.Context, db *sql.DB) ([]MigrationState, error) {
+	if err := createMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version, name, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int]MigrationState)
+	var unknown []MigrationState
+	for rows.Next() {
+		var state MigrationState
+		var appliedAt time.Time
+		if err := rows.Scan(&state.Version, &state.Name, &appliedAt); err != nil {
+			return nil, err
+		}
+		state.AppliedAt = &appliedAt
+		applied[state.Version] = state
+		if _, ok := findMigration(state.Version); !ok {
+			unknown = append(unknown, state)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations)+len(unknown))
+	for _, m := range migrations {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			state.AppliedAt = a.AppliedAt
+		}
+		states = append(states, state)
+	}
+	return append(states, unknown...), nil
+}
+
+func findMigration(version int) (Migration, bool) {
+	for _, m := range migrations {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
@@ -0,0 +1,56 @@
+package database_test
+
+import (
+	"cateogry-api/database"
+	"cateogry-api/internal/testdb"
+	"context"
+	"testing"
+)
+
+func TestMigrateDownAndUp(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	latest := database.LatestVersion()
+
+	if v, err := database.SchemaVersion(ctx, db); err != nil || v != latest {
+		t.Fatalf("SchemaVersion = %d, %v; want %d", v, err, latest)
+	}
+
+	if err := database.MigrateDown(ctx, db, 2); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := database.SchemaVersion(ctx, db); v != latest-2 {
+		t.Errorf("SchemaVersion after two steps down = %d, want %d", v, latest-2)
+	}
+	states, err := database.MigrationStatus(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := 0
+	for _, s := range states {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending != 2 {
+		t.Errorf("MigrationStatus lists %d pending migrations, want 2", pending)
+	}
+
+	if err := database.Migrate(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := database.SchemaVersion(ctx, db); v != latest {
+		t.Errorf("SchemaVersion after migrating up = %d, want %d", v, latest)
+	}
+
+	// Every migration can be reverted and applied again
+	if err := database.MigrateDown(ctx, db, latest); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := database.SchemaVersion(ctx, db); v != 0 {
+		t.Errorf("SchemaVersion after reverting everything = %d, want 0", v)
+	}
+	if err := database.Migrate(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,305 @@
+// Package config loads the service configuration from, in increasing order
+// of precedence, built-in defaults, a YAML or TOML file, a .env file and the
+// environment.
+//
+// Every key is nested by section, as in a config file:
+//
+//	db:
+//	  conn: postgres://app@localhost/app
+//
+// and is read from the environment as the upper-cased key with dots replaced
+// by underscores, DB_CONN here. PORT and REQUEST_TIMEOUT are still honoured
+// for server.port and server.request_timeout.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type Config struct {
+	Server      Server      `mapstructure:"server"`
+	Shutdown    Shutdown    `mapstructure:"shutdown"`
+	DB          DB          `mapstructure:"db"`
+	Tax         Tax         `mapstructure:"tax"`
+	Cart        Cart        `mapstructure:"cart"`
+	Reservation Reservation `mapstructure:"reservation"`
+	Cleanup     Cleanup     `mapstructure:"cleanup"`
+	Auth        Auth        `mapstructure:"auth"`
+	Log         Log         `mapstructure:"log"`
+	Tracing     Tracing     `mapstructure:"tracing"`
+	Swagger     Swagger     `mapstructure:"swagger"`
+	CORS        CORS        `mapstructure:"cors"`
+}
+
+type Server struct {
+	Port           string        `mapstructure:"port"`
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout    time.Duration `mapstructure:"idle_timeout"`
+	MaxHeaderBytes int           `mapstructure:"max_header_bytes"`
+	// RequestTimeout is the deadline of each API request; 0 disables it.
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+}
+
+type Shutdown struct {
+	// Timeout is how long in-flight requests get to finish.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Delay is how long the server keeps serving while reporting not ready.
+	Delay time.Duration `mapstructure:"delay"`
+}
+
+type DB struct {
+	Conn           string `mapstructure:"conn"`
+	SSLMode        string `mapstructure:"sslmode"`
+	SSLRootCert    string `mapstructure:"sslrootcert"`
+	PreferIPv4     bool   `mapstructure:"prefer_ipv4"`
+	SimpleProtocol bool   `mapstructure:"simple_protocol"`
+	// QueryTimeout limits each statement; 0 disables it.
+	QueryTimeout    time.Duration `mapstructure:"query_timeout"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+	// ConnectRetries is the number of startup retries; negative retries
+	// until the database answers.
+	ConnectRetries  int           `mapstructure:"connect_retries"`
+	RetryBackoff    time.Duration `mapstructure:"retry_backoff"`
+	RetryMaxBackoff time.Duration `mapstructure:"retry_max_backoff"`
+}
+
+type Tax struct {
+	PricesIncludeTax bool   `mapstructure:"prices_include_tax"`
+	Rounding         string `mapstructure:"rounding"`
+}
+
+type Cart struct {
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+type Reservation struct {
+	TTL            time.Duration `mapstructure:"ttl"`
+	MaxTTL         time.Duration `mapstructure:"max_ttl"`
+	ExpiryInterval time.Duration `mapstructure:"expiry_interval"`
+}
+
+type Cleanup struct {
+	// Retention is how long soft-deleted categories and products are kept.
+	Retention time.Duration `mapstructure:"retention"`
+	Interval  time.Duration `mapstructure:"interval"`
+}
+
+type Auth struct {
+	JWTSecret string `mapstructure:"jwt_secret"`
+	// JWTPublicKey is a PEM block or the path of a PEM file.
+	JWTPublicKey    string `mapstructure:"jwt_public_key"`
+	JWTIssuer       string `mapstructure:"jwt_issuer"`
+	JWTAudience     string `mapstructure:"jwt_audience"`
+	BootstrapAPIKey string `mapstructure:"bootstrap_api_key"`
+	RolePermissions string `mapstructure:"role_permissions"`
+}
+
+type Log struct {
+	Level  string `mapstructure:"level"`
+	Format string `mapstructure:"format"`
+}
+
+type Tracing struct {
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+type Swagger struct {
+	// Host is the host[:port] the Swagger UI sends requests to.
+	Host string `mapstructure:"host"`
+}
+
+type CORS struct {
+	// AllowedOrigins lists the origins browsers may call the API from; "*"
+	// allows any. Empty disables CORS.
+	AllowedOrigins []string      `mapstructure:"allowed_origins"`
+	AllowedMethods []string      `mapstructure:"allowed_methods"`
+	AllowedHeaders []string      `mapstructure:"allowed_headers"`
+	MaxAge         time.Duration `mapstructure:"max_age"`
+}
+
+// defaults holds every key, so that each can be set from the environment.
+var defaults = map[string]any{
+	"server.port":             "8080",
+	"server.read_timeout":     15 * time.Second,
+	"server.write_timeout":    35 * time.Second,
+	"server.idle_timeout":     2 * time.Minute,
+	"server.max_header_bytes": 1 << 20,
+	"server.request_timeout":  30 * time.Second,
+
+	"shutdown.timeout": 30 * time.Second,
+	"shutdown.delay":   time.Duration(0),
+
+	"db.conn":               "",
+	"db.sslmode":            "",
+	"db.sslrootcert":        "",
+	"db.prefer_ipv4":        false,
+	"db.simple_protocol":    false,
+	"db.query_timeout":      10 * time.Second,
+	"db.max_open_conns":     25,
+	"db.max_idle_conns":     5,
+	"db.conn_max_lifetime":  30 * time.Minute,
+	"db.conn_max_idle_time": 5 * time.Minute,
+	"db.connect_retries":    5,
+	"db.retry_backoff":      500 * time.Millisecond,
+	"db.retry_max_backoff":  30 * time.Second,
+
+	"tax.prices_include_tax": false,
+	"tax.rounding":           "",
+
+	"cart.ttl": 2 * time.Hour,
+
+	"reservation.ttl":             15 * time.Minute,
+	"reservation.max_ttl":         24 * time.Hour,
+	"reservation.expiry_interval": time.Minute,
+
+	"cleanup.retention": 30 * 24 * time.Hour,
+	"cleanup.interval":  24 * time.Hour,
+
+	"auth.jwt_secret":        "",
+	"auth.jwt_public_key":    "",
+	"auth.jwt_issuer":        "",
+	"auth.jwt_audience":      "",
+	"auth.bootstrap_api_key": "",
+	"auth.role_permissions":  "",
+
+	"log.level":  "info",
+	"log.format": "json",
+
+	"tracing.exporter":     "none",
+	"tracing.endpoint":     "",
+	"tracing.insecure":     false,
+	"tracing.service_name": "cateogry-api",
+	"tracing.sample_ratio": 1.0,
+
+	"swagger.host": "localhost:8080",
+
+	"cors.allowed_origins": []string{},
+	"cors.allowed_methods": []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+	"cors.allowed_headers": []string{"Content-Type", "Authorization", "X-API-Key", "X-Request-ID"},
+	"cors.max_age":         10 * time.Minute,
+}
+
+// envAliases are environment variables read in addition to the derived name.
+var envAliases = map[string]string{
+	"server.port":            "PORT",
+	"server.request_timeout": "REQUEST_TIMEOUT",
+}
+
+// secrets are printed redacted.
+var secrets = map[string]bool{
+	"auth.jwt_secret":        true,
+	"auth.bootstrap_api_key": true,
+}
+
+// fileCandidates are looked up in the working directory when no config file
+// is named.
+var fileCandidates = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Loaded is the result of Load: the configuration and where it came from.
+type Loaded struct {
+	Config
+	// File is the config file read, if any.
+	File string
+	v    *viper.Viper
+}
+
+// Load reads the configuration. file names a YAML or TOML config file; when
+// empty, the first of config.yaml, config.yml and config.toml in the working
+// directory is used if present. A .env file in the working directory is
+// read too, but variables set in the environment take precedence over it.
+// Load does not validate; call Validate.
+func Load(file string) (*Loaded, error) {
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+		if err := v.BindEnv(envNames(key)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if file == "" {
+		for _, candidate := range fileCandidates {
+			if _, err := os.Stat(candidate); err == nil {
+				file = candidate
+				break
+			}
+		}
+	}
+	if file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("reading config file %s: %w", file, err)
+		}
+	}
+
+	if err := loadDotEnv(v, ".env"); err != nil {
+		return nil, err
+	}
+
+	var c Config
+	if err := v.Unmarshal(&c); err != nil {
+		return nil, fmt.Errorf("decoding configuration: %w", err)
+	}
+	return &Loaded{Config: c, File: file, v: v}, nil
+}
+
+// envNames returns key followed by the environment variables it is read from.
+func envNames(key string) []string {
+	names := []string{key, strings.ToUpper(strings.ReplaceAll(key, ".", "_"))}
+	if alias, ok := envAliases[key]; ok {
+		names = append(names, alias)
+	}
+	return names
+}
+
+// loadDotEnv applies the variables in a .env file for every key whose
+// environment variables are all unset.
+func loadDotEnv(v *viper.Viper, path string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	dotenv := viper.New()
+	dotenv.SetConfigFile(path)
+	dotenv.SetConfigType("env")
+	if err := dotenv.ReadInConfig(); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	for key := range defaults {
+		envs := envNames(key)[1:]
+		if inEnvironment(envs) {
+			continue
+		}
+		for _, env := range envs {
+			// viper lower-cases the names it reads from .env
+			if name := strings.ToLower(env); dotenv.IsSet(name) {
+				v.Set(key, dotenv.Get(name))
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func inEnvironment(envs []string) bool {
+	for _, env := range envs {
+		if _, ok := os.LookupEnv(env); ok {
+			return true
+		}
+	}
+	return false
+}
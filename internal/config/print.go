@@ -0,0 +1,49 @@
+package config
+
+import (
+	"cateogry-api/database"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Print writes every effective setting with the environment variables it
+// can be set from. Secrets are replaced by xxxxx and the password in db.conn
+// is redacted.
+func (l *Loaded) Print(w io.Writer) error {
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if l.File != "" {
+		fmt.Fprintf(w, "# config file: %s\n", l.File)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tENV\tVALUE")
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, strings.Join(envNames(key)[1:], ","), l.display(key))
+	}
+	return tw.Flush()
+}
+
+func (l *Loaded) display(key string) string {
+	value := l.v.Get(key)
+	switch {
+	case key == "db.conn":
+		return database.Redact(l.v.GetString(key))
+	case secrets[key]:
+		if l.v.GetString(key) == "" {
+			return ""
+		}
+		return "xxxxx"
+	}
+	switch value.(type) {
+	case []string, []any:
+		return strings.Join(l.v.GetStringSlice(key), ",")
+	}
+	return fmt.Sprint(value)
+}
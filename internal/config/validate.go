@@ -0,0 +1,121 @@
+package config
+
+import (
+	"cateogry-api/internal/logging"
+	"cateogry-api/internal/service"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+var traceExporters = []string{"none", "stdout", "otlp"}
+
+// Validate reports every invalid setting at once, each prefixed with its key.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	positive := func(key string, d time.Duration) {
+		if d <= 0 {
+			fail(key, "must be a positive duration, got %s", d)
+		}
+	}
+	notNegative := func(key string, d time.Duration) {
+		if d < 0 {
+			fail(key, "must not be negative, got %s", d)
+		}
+	}
+	oneOf := func(key, value string, allowed []string) {
+		for _, a := range allowed {
+			if strings.EqualFold(value, a) {
+				return
+			}
+		}
+		fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		fail("server.port", "must be a port number, got %q", c.Server.Port)
+	}
+	positive("server.read_timeout", c.Server.ReadTimeout)
+	positive("server.write_timeout", c.Server.WriteTimeout)
+	positive("server.idle_timeout", c.Server.IdleTimeout)
+	notNegative("server.request_timeout", c.Server.RequestTimeout)
+	if c.Server.RequestTimeout > 0 && c.Server.WriteTimeout <= c.Server.RequestTimeout {
+		fail("server.write_timeout", "must be longer than server.request_timeout (%s) so timed out requests can still answer", c.Server.RequestTimeout)
+	}
+	if c.Server.MaxHeaderBytes <= 0 {
+		fail("server.max_header_bytes", "must be positive, got %d", c.Server.MaxHeaderBytes)
+	}
+	positive("shutdown.timeout", c.Shutdown.Timeout)
+	notNegative("shutdown.delay", c.Shutdown.Delay)
+
+	if c.DB.Conn == "" {
+		fail("db.conn", "is required")
+	}
+	if c.DB.SSLMode != "" {
+		oneOf("db.sslmode", c.DB.SSLMode, sslModes)
+	}
+	if c.DB.SSLRootCert != "" {
+		if _, err := os.Stat(c.DB.SSLRootCert); err != nil {
+			fail("db.sslrootcert", "%v", err)
+		}
+	}
+	notNegative("db.query_timeout", c.DB.QueryTimeout)
+	if c.DB.MaxOpenConns <= 0 {
+		fail("db.max_open_conns", "must be positive, got %d", c.DB.MaxOpenConns)
+	}
+	if c.DB.MaxIdleConns < 0 || c.DB.MaxIdleConns > c.DB.MaxOpenConns {
+		fail("db.max_idle_conns", "must be between 0 and db.max_open_conns (%d), got %d", c.DB.MaxOpenConns, c.DB.MaxIdleConns)
+	}
+	notNegative("db.conn_max_lifetime", c.DB.ConnMaxLifetime)
+	notNegative("db.conn_max_idle_time", c.DB.ConnMaxIdleTime)
+	positive("db.retry_backoff", c.DB.RetryBackoff)
+	if c.DB.RetryMaxBackoff < c.DB.RetryBackoff {
+		fail("db.retry_max_backoff", "must be at least db.retry_backoff (%s), got %s", c.DB.RetryBackoff, c.DB.RetryMaxBackoff)
+	}
+
+	if _, err := service.ParseRoundingMode(c.Tax.Rounding); err != nil {
+		fail("tax.rounding", "%v", err)
+	}
+	positive("cart.ttl", c.Cart.TTL)
+	positive("reservation.ttl", c.Reservation.TTL)
+	if c.Reservation.MaxTTL < c.Reservation.TTL {
+		fail("reservation.max_ttl", "must be at least reservation.ttl (%s), got %s", c.Reservation.TTL, c.Reservation.MaxTTL)
+	}
+	positive("reservation.expiry_interval", c.Reservation.ExpiryInterval)
+	positive("cleanup.retention", c.Cleanup.Retention)
+	positive("cleanup.interval", c.Cleanup.Interval)
+
+	if _, err := service.ParseRolePermissions(c.Auth.RolePermissions); err != nil {
+		fail("auth.role_permissions", "%v", err)
+	}
+
+	if _, err := logging.New(io.Discard, c.Log.Level, c.Log.Format); err != nil {
+		fail("log", "%v", err)
+	}
+
+	oneOf("tracing.exporter", c.Tracing.Exporter, traceExporters)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("tracing.sample_ratio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	if c.Swagger.Host == "" {
+		fail("swagger.host", "is required")
+	}
+	notNegative("cors.max_age", c.CORS.MaxAge)
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			fail("cors.allowed_origins", "%q is not \"*\" or an http(s) origin", origin)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditEntry records one change to an entity. Before is empty for creates
+// and After for deletes; Changes lists only the top-level fields that differ.
+type AuditEntry struct {
+	ID         int                    `json:"id"`
+	Actor      string                 `json:"actor"`
+	Action     AuditAction            `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   int                    `json:"entity_id"`
+	Before     json.RawMessage        `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage        `json:"after,omitempty" swaggertype:"object"`
+	Changes    map[string]FieldChange `json:"changes"`
+	RequestID  string                 `json:"request_id,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditFilter narrows GET /audit. Zero fields do not filter.
+type AuditFilter struct {
+	Actor      string
+	Action     AuditAction
+	EntityType string
+	EntityID   int
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
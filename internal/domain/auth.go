@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// APIKey identifies a machine caller. Only a hash of the key is stored; Key is
+// filled in once, in the response that creates it.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Role       Role       `json:"role"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Principal is the authenticated caller of a request. Subject is unique per
+// caller and prefixed with Method, as in "api_key:12" or "jwt:alice".
+type Principal struct {
+	Subject  string `json:"subject"`
+	Method   string `json:"method"` // "api_key" or "jwt"
+	Role     Role   `json:"role"`
+	APIKeyID int    `json:"api_key_id,omitempty"`
+}
+
+// Role names a set of permissions. Which permissions each role grants is
+// configurable; these are the roles that come with defaults.
+type Role string
+
+const (
+	RoleAdmin   Role = "admin"
+	RoleCashier Role = "cashier"
+	RoleViewer  Role = "viewer"
+)
+
+// Permission is the right to use one group of routes.
+type Permission string
+
+const (
+	PermCategoriesRead      Permission = "categories:read"
+	PermCategoriesWrite     Permission = "categories:write"
+	PermProductsRead        Permission = "products:read"
+	PermProductsWrite       Permission = "products:write"
+	PermLocationsRead       Permission = "locations:read"
+	PermLocationsWrite      Permission = "locations:write"
+	PermSuppliersRead       Permission = "suppliers:read"
+	PermSuppliersWrite      Permission = "suppliers:write"
+	PermPurchaseOrdersRead  Permission = "purchase_orders:read"
+	PermPurchaseOrdersWrite Permission = "purchase_orders:write"
+	PermPromotionsRead      Permission = "promotions:read"
+	PermPromotionsWrite     Permission = "promotions:write"
+	PermTaxesRead           Permission = "taxes:read"
+	PermTaxesWrite          Permission = "taxes:write"
+	PermCheckoutCreate      Permission = "checkout:create"
+	PermTransactionsRead    Permission = "transactions:read"
+	PermReportsRead         Permission = "reports:read"
+	PermAPIKeysManage       Permission = "api_keys:manage"
+	PermAuditRead           Permission = "audit:read"
+)
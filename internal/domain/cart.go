@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type CartStatus string
+
+const (
+	CartOpen       CartStatus = "open"
+	CartCheckedOut CartStatus = "checked_out"
+)
+
+// Cart is a parked order. Item prices and stock are read live from products
+// every time the cart is fetched; nothing is reserved until checkout.
+type Cart struct {
+	ID            int        `json:"id"`
+	Status        CartStatus `json:"status"`
+	TransactionID *int       `json:"transaction_id,omitempty"`
+	Items         []CartItem `json:"items"`
+	Subtotal      int        `json:"subtotal"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type CartItem struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	UnitPrice   int    `json:"unit_price"`
+	Quantity    int    `json:"quantity"`
+	Subtotal    int    `json:"subtotal"`
+	Stock       int    `json:"stock"`
+	InStock     bool   `json:"in_stock"`
+}
+
+type CartCheckoutRequest struct {
+	LocationID int               `json:"location_id,omitempty"`
+	PromoCodes []string          `json:"promo_codes,omitempty"`
+	Payments   []CheckoutPayment `json:"payments,omitempty"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type LocationType string
+
+const (
+	LocationStore     LocationType = "store"
+	LocationWarehouse LocationType = "warehouse"
+)
+
+// Location is a store or warehouse holding stock. Exactly one location is the
+// default; it is used when a request does not name a location.
+type Location struct {
+	ID        int          `json:"id"`
+	Name      string       `json:"name"`
+	Type      LocationType `json:"type"`
+	Address   string       `json:"address,omitempty"`
+	IsDefault bool         `json:"is_default"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	DeletedAt *time.Time   `json:"-"` // Hidden from JSON
+}
+
+// ProductStock is a product's stock level at one location.
+type ProductStock struct {
+	LocationID   int    `json:"location_id"`
+	LocationName string `json:"location_name"`
+	Quantity     int    `json:"quantity"`
+}
+
+type StockTransfer struct {
+	ID             int       `json:"id"`
+	ProductID      int       `json:"product_id"`
+	FromLocationID int       `json:"from_location_id"`
+	ToLocationID   int       `json:"to_location_id"`
+	Quantity       int       `json:"quantity"`
+	Note           string    `json:"note,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+type PaymentMethod string
+
+const (
+	PaymentCash    PaymentMethod = "cash"
+	PaymentCard    PaymentMethod = "card"
+	PaymentEWallet PaymentMethod = "ewallet"
+	PaymentQRIS    PaymentMethod = "qris"
+)
+
+// CheckoutPayment is what the customer hands over with one method. For cash,
+// Amount may exceed what is owed and the difference is returned as change.
+type CheckoutPayment struct {
+	Method    PaymentMethod `json:"method"`
+	Amount    int           `json:"amount"`
+	Reference string        `json:"reference,omitempty"`
+}
+
+// Payment is a recorded payment. Amount is the part of the total settled by
+// this payment; Tendered and Change are only different from Amount for cash.
+type Payment struct {
+	ID            int           `json:"id"`
+	TransactionID int           `json:"transaction_id"`
+	Method        PaymentMethod `json:"method"`
+	Amount        int           `json:"amount"`
+	Tendered      int           `json:"tendered"`
+	Change        int           `json:"change"`
+	Reference     string        `json:"reference,omitempty"`
+}
+
+type PaymentMethodSummary struct {
+	Method       PaymentMethod `json:"metode"`
+	Transactions int           `json:"jumlah_transaksi"`
+	Amount       int           `json:"total"`
+}
@@ -3,14 +3,17 @@ package domain
 import "time"
 
 type Product struct {
-	ID           int        `json:"id"`
-	Name         string     `json:"name"`
-	Description  string     `json:"description"`
-	Price        int        `json:"price"`
-	Stock        int        `json:"stock"`
-	CategoryID   int        `json:"category_id"`
-	CategoryName string     `json:"category_name,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
-	DeletedAt    *time.Time `json:"-"` // Hidden from JSON
+	ID             int            `json:"id"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description"`
+	Price          int            `json:"price"`
+	CostPrice      int            `json:"cost_price"`          // What one unit costs to buy
+	Stock          int            `json:"stock"`               // Total over all locations
+	AvailableStock int            `json:"available_stock"`     // Stock minus active reservations
+	Locations      []ProductStock `json:"locations,omitempty"` // Stock per location
+	CategoryID     int            `json:"category_id"`
+	CategoryName   string         `json:"category_name,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      *time.Time     `json:"-"` // Hidden from JSON
 }
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+type PromotionType string
+
+const (
+	PromotionPercentage PromotionType = "percentage"  // Value is a percentage (1-100) off eligible lines
+	PromotionFixed      PromotionType = "fixed"       // Value is an amount off eligible lines
+	PromotionBuyXGetY   PromotionType = "buy_x_get_y" // Every BuyQuantity+GetQuantity units, the cheapest GetQuantity are free
+)
+
+// Promotion is a discount rule. A promotion without a Code is applied
+// automatically; one with a Code only when the code is sent at checkout.
+// ProductID and CategoryID narrow which lines are eligible; when both are nil
+// the whole cart is eligible.
+type Promotion struct {
+	ID          int           `json:"id"`
+	Code        string        `json:"code,omitempty"`
+	Name        string        `json:"name"`
+	Type        PromotionType `json:"type"`
+	Value       int           `json:"value"`
+	ProductID   *int          `json:"product_id,omitempty"`
+	CategoryID  *int          `json:"category_id,omitempty"`
+	BuyQuantity int           `json:"buy_quantity,omitempty"`
+	GetQuantity int           `json:"get_quantity,omitempty"`
+	Stackable   bool          `json:"stackable"`
+	Priority    int           `json:"priority"`
+	Active      bool          `json:"active"`
+	StartsAt    *time.Time    `json:"starts_at,omitempty"`
+	EndsAt      *time.Time    `json:"ends_at,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	DeletedAt   *time.Time    `json:"-"` // Hidden from JSON
+}
+
+// IsValidAt reports whether the promotion is active and inside its validity window.
+func (p Promotion) IsValidAt(t time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !t.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// AppliedDiscount is one promotion applied to a transaction. ProductID is set
+// when the discount belongs to a single line and 0 for cart-wide discounts.
+type AppliedDiscount struct {
+	ID            int           `json:"id"`
+	TransactionID int           `json:"transaction_id"`
+	PromotionID   int           `json:"promotion_id"`
+	Code          string        `json:"code,omitempty"`
+	Name          string        `json:"name"`
+	Type          PromotionType `json:"type"`
+	ProductID     int           `json:"product_id,omitempty"`
+	Amount        int           `json:"amount"`
+}
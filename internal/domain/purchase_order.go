@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderOrdered           PurchaseOrderStatus = "ordered"
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderReceived          PurchaseOrderStatus = "received"
+)
+
+// PurchaseOrder is stock ordered from a supplier for one location. It can be
+// edited while draft and receives stock once ordered.
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	LocationID   int                 `json:"location_id"`
+	Status       PurchaseOrderStatus `json:"status"`
+	Note         string              `json:"note,omitempty"`
+	Items        []PurchaseOrderItem `json:"items"`
+	TotalCost    int                 `json:"total_cost"`
+	OrderedAt    *time.Time          `json:"ordered_at,omitempty"`
+	ReceivedAt   *time.Time          `json:"received_at,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// PurchaseOrderItem is one product on a purchase order. UnitCost is the cost
+// price agreed with the supplier.
+type PurchaseOrderItem struct {
+	ID               int    `json:"id"`
+	ProductID        int    `json:"product_id"`
+	ProductName      string `json:"product_name,omitempty"`
+	QuantityOrdered  int    `json:"quantity_ordered"`
+	QuantityReceived int    `json:"quantity_received"`
+	UnitCost         int    `json:"unit_cost"`
+}
+
+// ReceiveRequest lists the quantities that arrived for a purchase order.
+type ReceiveRequest struct {
+	Items []CheckoutItem `json:"items"`
+}
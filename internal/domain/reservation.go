@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationActive   ReservationStatus = "active"
+	ReservationConsumed ReservationStatus = "consumed"
+	ReservationReleased ReservationStatus = "released"
+	ReservationExpired  ReservationStatus = "expired"
+)
+
+// StockReservation holds product quantities for an order that has not been
+// paid yet. While active and unexpired the quantities are subtracted from
+// every product's available stock. Only CreatedBy, the subject of the
+// caller that made it, can check it out or release it.
+type StockReservation struct {
+	ID            int               `json:"id"`
+	Status        ReservationStatus `json:"status"`
+	CreatedBy     string            `json:"created_by"`
+	Items         []CheckoutItem    `json:"items"`
+	TransactionID *int              `json:"transaction_id,omitempty"`
+	ExpiresAt     time.Time         `json:"expires_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+type ReservationRequest struct {
+	Items      []CheckoutItem `json:"items"`
+	TTLSeconds int            `json:"ttl_seconds,omitempty"`
+}
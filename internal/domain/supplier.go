@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type Supplier struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	ContactName string     `json:"contact_name,omitempty"`
+	Phone       string     `json:"phone,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	Address     string     `json:"address,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"-"` // Hidden from JSON
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// TaxRate is a tax percentage expressed in basis points (1100 = 11%).
+// A rate with a CategoryID applies to that category; the rate without one is
+// the default for every other product.
+type TaxRate struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	CategoryID *int      `json:"category_id,omitempty"`
+	Rate       int       `json:"rate"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type TaxSummary struct {
+	Rate          int `json:"tarif"`
+	TaxableAmount int `json:"dasar_pengenaan"`
+	TaxAmount     int `json:"pajak"`
+}
@@ -2,20 +2,40 @@ package domain
 
 import "time"
 
+// Transaction totals: Subtotal is the sum of line subtotals before discounts,
+// TotalAmount is the grand total the customer pays. TaxInclusive records
+// whether TaxAmount was already contained in the prices or added on top.
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID             int                 `json:"id"`
+	LocationID     int                 `json:"location_id"`
+	Subtotal       int                 `json:"subtotal"`
+	DiscountAmount int                 `json:"discount_amount"`
+	TaxAmount      int                 `json:"tax_amount"`
+	TaxInclusive   bool                `json:"tax_inclusive"`
+	TotalAmount    int                 `json:"total_amount"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Details        []TransactionDetail `json:"details"`
+	Discounts      []AppliedDiscount   `json:"discounts"`
+	Payments       []Payment           `json:"payments"`
 }
 
+// TransactionDetail keeps a snapshot of the product as it was sold, so it stays
+// accurate after the product is renamed, repriced or purged. ProductID is 0
+// once the product row no longer exists.
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID             int    `json:"id"`
+	TransactionID  int    `json:"transaction_id"`
+	ProductID      int    `json:"product_id"`
+	ProductName    string `json:"product_name"`
+	CategoryID     int    `json:"category_id"`
+	CategoryName   string `json:"category_name"`
+	UnitPrice      int    `json:"unit_price"`
+	UnitCost       int    `json:"unit_cost"`
+	Quantity       int    `json:"quantity"`
+	Subtotal       int    `json:"subtotal"`
+	DiscountAmount int    `json:"discount_amount"`
+	TaxRate        int    `json:"tax_rate"`
+	TaxAmount      int    `json:"tax_amount"`
 }
 
 type CheckoutItem struct {
@@ -23,17 +43,81 @@ type CheckoutItem struct {
 	Quantity  int `json:"quantity"`
 }
 
+// CheckoutRequest is the body of POST /checkout. Payments may be omitted, in
+// which case the total is recorded as paid in exact cash. When ReservationID is
+// set, the stock held by that reservation is available to this checkout and
+// the reservation is consumed. Stock is taken from LocationID, or from the
+// default location when it is 0.
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items         []CheckoutItem    `json:"items"`
+	LocationID    int               `json:"location_id,omitempty"`
+	ReservationID int               `json:"reservation_id,omitempty"`
+	PromoCodes    []string          `json:"promo_codes,omitempty"`
+	Payments      []CheckoutPayment `json:"payments,omitempty"`
+	// IdempotencyKey comes from the Idempotency-Key header. A checkout with
+	// a key already used returns the transaction created the first time, if
+	// it comes from the same Caller with the same RequestHash.
+	IdempotencyKey string `json:"-"`
+	// RequestHash identifies what the checkout asks for, set by the service.
+	RequestHash string `json:"-"`
+	// CartID is set when a cart is checked out, in place of Items. The cart
+	// must still be open and is marked checked out by the same database
+	// transaction as the sale.
+	CartID int `json:"-"`
+	// Caller is the subject of the authenticated caller, set by the service.
+	// A reservation can only be consumed by the caller that created it.
+	Caller string `json:"-"`
 }
 
 type DailyReport struct {
-	TotalRevenue       int                `json:"total_revenue"`
-	TotalTransactions  int                `json:"total_transaksi"`
-	BestSellingProduct BestSellingProduct `json:"produk_terlaris"`
+	TotalRevenue       int                    `json:"total_revenue"`
+	TotalTransactions  int                    `json:"total_transaksi"`
+	TotalDiscount      int                    `json:"total_diskon"`
+	TotalTax           int                    `json:"total_pajak"`
+	BestSellingProduct BestSellingProduct     `json:"produk_terlaris"`
+	Promotions         []PromotionUsage       `json:"promo"`
+	Taxes              []TaxSummary           `json:"pajak"`
+	PaymentMethods     []PaymentMethodSummary `json:"metode_pembayaran"`
+	NetSales           int                    `json:"penjualan_bersih"`
+	TotalCOGS          int                    `json:"total_hpp"`
+	GrossProfit        int                    `json:"laba_kotor"`
+	MarginPercent      float64                `json:"margin_persen"`
+	ProductMargins     []ProductMargin        `json:"margin_produk"`
+	CategoryMargins    []CategoryMargin       `json:"margin_kategori"`
 }
 
 type BestSellingProduct struct {
 	Name    string `json:"nama"`
 	QtySold int    `json:"qty_terjual"`
 }
+
+// ProductMargin is the gross margin of one product. NetSales is what was
+// charged after discounts and without tax; COGS is the cost price snapshot
+// taken at checkout times the quantity sold.
+type ProductMargin struct {
+	ProductID     int     `json:"product_id"`
+	Name          string  `json:"nama"`
+	QtySold       int     `json:"qty_terjual"`
+	NetSales      int     `json:"penjualan_bersih"`
+	COGS          int     `json:"hpp"`
+	GrossProfit   int     `json:"laba_kotor"`
+	MarginPercent float64 `json:"margin_persen"`
+}
+
+type CategoryMargin struct {
+	CategoryID    int     `json:"category_id"`
+	Name          string  `json:"nama"`
+	QtySold       int     `json:"qty_terjual"`
+	NetSales      int     `json:"penjualan_bersih"`
+	COGS          int     `json:"hpp"`
+	GrossProfit   int     `json:"laba_kotor"`
+	MarginPercent float64 `json:"margin_persen"`
+}
+
+type PromotionUsage struct {
+	PromotionID   int    `json:"promotion_id"`
+	Name          string `json:"nama"`
+	Code          string `json:"kode,omitempty"`
+	TimesApplied  int    `json:"digunakan"`
+	TotalDiscount int    `json:"total_diskon"`
+}
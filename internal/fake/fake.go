@@ -0,0 +1,457 @@
+// Package fake has in-memory repositories for tests of the services and
+// handlers that do not need Postgres. They keep only what the services
+// observe: no soft deletes, locations or reservations.
+package fake
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFailed is returned by every method of a repository whose Err is set
+// to it, to test how failures surface.
+var ErrFailed = errors.New("fake repository failure")
+
+var (
+	_ repository.ProductRepository     = (*ProductRepository)(nil)
+	_ repository.TransactionRepository = (*TransactionRepository)(nil)
+	_ repository.PromotionRepository   = (*PromotionRepository)(nil)
+	_ repository.TaxRateRepository     = (*TaxRateRepository)(nil)
+)
+
+type ProductRepository struct {
+	mu       sync.Mutex
+	products map[int]domain.Product
+	nextID   int
+	// Err, when set, is returned by every method.
+	Err error
+}
+
+func NewProductRepository(products ...domain.Product) *ProductRepository {
+	r := &ProductRepository{products: make(map[int]domain.Product)}
+	for _, p := range products {
+		r.Create(context.Background(), p)
+	}
+	return r
+}
+
+func (r *ProductRepository) GetAll(ctx context.Context, name string) ([]domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	products := make([]domain.Product, 0, len(r.products))
+	for id := 1; id <= r.nextID; id++ {
+		if p, ok := r.products[id]; ok && containsFold(p.Name, name) {
+			products = append(products, p)
+		}
+	}
+	return products, nil
+}
+
+// GetByCategory returns the products with categoryID. The fake has no
+// categories, so it never returns repository.ErrCategoryNotFound.
+func (r *ProductRepository) GetByCategory(ctx context.Context, categoryID int) ([]domain.Product, error) {
+	products, err := r.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	inCategory := make([]domain.Product, 0, len(products))
+	for _, p := range products {
+		if p.CategoryID == categoryID {
+			inCategory = append(inCategory, p)
+		}
+	}
+	return inCategory, nil
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id int) (*domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	p, ok := r.products[id]
+	if !ok {
+		return nil, repository.ErrProductNotFound
+	}
+	return &p, nil
+}
+
+func (r *ProductRepository) Create(ctx context.Context, p domain.Product) (domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return domain.Product{}, r.Err
+	}
+	r.nextID++
+	p.ID = r.nextID
+	p.AvailableStock = p.Stock
+	p.CreatedAt, p.UpdatedAt = time.Now(), time.Now()
+	r.products[p.ID] = p
+	return p, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, id int, p domain.Product) (*domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	old, ok := r.products[id]
+	if !ok {
+		return nil, repository.ErrProductNotFound
+	}
+	p.ID, p.CreatedAt, p.UpdatedAt = id, old.CreatedAt, time.Now()
+	p.AvailableStock = p.Stock
+	r.products[id] = p
+	return &p, nil
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	if _, ok := r.products[id]; !ok {
+		return repository.ErrProductNotFound
+	}
+	delete(r.products, id)
+	return nil
+}
+
+// take removes quantity from the stock of a product, as a checkout does.
+func (r *ProductRepository) take(id, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.products[id]
+	if !ok {
+		return fmt.Errorf("%w: id %d", repository.ErrProductNotFound, id)
+	}
+	if p.Stock < quantity {
+		return fmt.Errorf("%w: stock for product %s is not enough. available: %d, requested: %d",
+			repository.ErrInsufficientStock, p.Name, p.Stock, quantity)
+	}
+	p.Stock -= quantity
+	p.AvailableStock = p.Stock
+	r.products[id] = p
+	return nil
+}
+
+// TransactionRepository records checkouts of the products in a
+// ProductRepository.
+type TransactionRepository struct {
+	products *ProductRepository
+
+	mu           sync.Mutex
+	transactions []domain.Transaction
+	keys         map[string]idempotencyKey
+	// Err, when set, is returned by every method.
+	Err error
+}
+
+// idempotencyKey is the checkout an idempotency key was used for.
+type idempotencyKey struct {
+	transactionID int
+	caller, hash  string
+}
+
+func NewTransactionRepository(products *ProductRepository) *TransactionRepository {
+	return &TransactionRepository{products: products, keys: make(map[string]idempotencyKey)}
+}
+
+// CreateTransaction prices the items from the products, lets price finish
+// the totals and takes the items out of stock. Unlike Postgres it does not
+// roll back stock taken for earlier items when a later one fails.
+func (r *TransactionRepository) CreateTransaction(ctx context.Context, req domain.CheckoutRequest, price repository.PriceFunc) (*domain.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if key, ok := r.keys[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		if key.caller != req.Caller || key.hash != req.RequestHash {
+			return nil, repository.ErrIdempotencyKeyUsed
+		}
+		return nil, repository.ErrIdempotencyKeyReplayed
+	}
+
+	t := &domain.Transaction{LocationID: req.LocationID, Discounts: make([]domain.AppliedDiscount, 0)}
+	for _, item := range req.Items {
+		p, err := r.products.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: id %d", repository.ErrProductNotFound, item.ProductID)
+		}
+		if p.Stock < item.Quantity {
+			return nil, fmt.Errorf("%w: stock for product %s is not enough. available: %d, requested: %d",
+				repository.ErrInsufficientStock, p.Name, p.Stock, item.Quantity)
+		}
+		line := p.Price * item.Quantity
+		t.Subtotal += line
+		t.Details = append(t.Details, domain.TransactionDetail{
+			ProductID:    p.ID,
+			ProductName:  p.Name,
+			CategoryID:   p.CategoryID,
+			CategoryName: p.CategoryName,
+			UnitPrice:    p.Price,
+			UnitCost:     p.CostPrice,
+			Quantity:     item.Quantity,
+			Subtotal:     line,
+		})
+	}
+	t.TotalAmount = t.Subtotal
+	if price != nil {
+		if err := price(t); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range req.Items {
+		if err := r.products.take(item.ProductID, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	t.ID = len(r.transactions) + 1
+	t.CreatedAt = time.Now()
+	r.transactions = append(r.transactions, *t)
+	if req.IdempotencyKey != "" {
+		r.keys[req.IdempotencyKey] = idempotencyKey{transactionID: t.ID, caller: req.Caller, hash: req.RequestHash}
+	}
+	return t, nil
+}
+
+func (r *TransactionRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	r.mu.Lock()
+	k, ok := r.keys[key]
+	r.mu.Unlock()
+	if !ok {
+		return nil, repository.ErrTransactionNotFound
+	}
+	return r.GetByID(ctx, k.transactionID)
+}
+
+func (r *TransactionRepository) GetByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if id < 1 || id > len(r.transactions) {
+		return nil, repository.ErrTransactionNotFound
+	}
+	t := r.transactions[id-1]
+	return &t, nil
+}
+
+func (r *TransactionRepository) GetDailyReport(ctx context.Context, date time.Time) (domain.DailyReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return r.GetReport(ctx, start, start.AddDate(0, 0, 1))
+}
+
+// GetReport fills in the totals only, not the breakdowns.
+func (r *TransactionRepository) GetReport(ctx context.Context, startDate, endDate time.Time) (domain.DailyReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var report domain.DailyReport
+	if r.Err != nil {
+		return report, r.Err
+	}
+	for _, t := range r.transactions {
+		if t.CreatedAt.Before(startDate) || !t.CreatedAt.Before(endDate) {
+			continue
+		}
+		report.TotalTransactions++
+		report.TotalRevenue += t.TotalAmount
+		report.TotalDiscount += t.DiscountAmount
+		report.TotalTax += t.TaxAmount
+	}
+	return report, nil
+}
+
+// PromotionRepository holds promotions; GetValidAt filters them with
+// Promotion.IsValidAt.
+type PromotionRepository struct {
+	mu         sync.Mutex
+	promotions []domain.Promotion
+	// Err, when set, is returned by every method.
+	Err error
+}
+
+func NewPromotionRepository(promotions ...domain.Promotion) *PromotionRepository {
+	r := &PromotionRepository{}
+	for _, p := range promotions {
+		r.Create(context.Background(), p)
+	}
+	return r
+}
+
+func (r *PromotionRepository) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return append([]domain.Promotion(nil), r.promotions...), nil
+}
+
+func (r *PromotionRepository) GetValidAt(ctx context.Context, t time.Time) ([]domain.Promotion, error) {
+	all, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var valid []domain.Promotion
+	for _, p := range all {
+		if p.IsValidAt(t) {
+			valid = append(valid, p)
+		}
+	}
+	return valid, nil
+}
+
+func (r *PromotionRepository) GetByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	for _, p := range r.promotions {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, errors.New("promotion not found")
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return domain.Promotion{}, r.Err
+	}
+	p.ID = len(r.promotions) + 1
+	r.promotions = append(r.promotions, p)
+	return p, nil
+}
+
+func (r *PromotionRepository) Update(ctx context.Context, id int, p domain.Promotion) (*domain.Promotion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	for i := range r.promotions {
+		if r.promotions[i].ID == id {
+			p.ID = id
+			r.promotions[i] = p
+			return &p, nil
+		}
+	}
+	return nil, errors.New("promotion not found")
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	for i := range r.promotions {
+		if r.promotions[i].ID == id {
+			r.promotions = append(r.promotions[:i], r.promotions[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("promotion not found")
+}
+
+type TaxRateRepository struct {
+	mu    sync.Mutex
+	rates []domain.TaxRate
+	// Err, when set, is returned by every method.
+	Err error
+}
+
+func NewTaxRateRepository(rates ...domain.TaxRate) *TaxRateRepository {
+	r := &TaxRateRepository{}
+	for _, t := range rates {
+		r.Create(context.Background(), t)
+	}
+	return r
+}
+
+func (r *TaxRateRepository) GetAll(ctx context.Context) ([]domain.TaxRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return append([]domain.TaxRate(nil), r.rates...), nil
+}
+
+func (r *TaxRateRepository) GetByID(ctx context.Context, id int) (*domain.TaxRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	for _, t := range r.rates {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, repository.ErrTaxRateNotFound
+}
+
+func (r *TaxRateRepository) Create(ctx context.Context, t domain.TaxRate) (domain.TaxRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return domain.TaxRate{}, r.Err
+	}
+	t.ID = len(r.rates) + 1
+	r.rates = append(r.rates, t)
+	return t, nil
+}
+
+func (r *TaxRateRepository) Update(ctx context.Context, id int, t domain.TaxRate) (*domain.TaxRate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	for i := range r.rates {
+		if r.rates[i].ID == id {
+			t.ID = id
+			r.rates[i] = t
+			return &t, nil
+		}
+	}
+	return nil, repository.ErrTaxRateNotFound
+}
+
+func (r *TaxRateRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.Err != nil {
+		return r.Err
+	}
+	for i := range r.rates {
+		if r.rates[i].ID == id {
+			r.rates = append(r.rates[:i], r.rates[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrTaxRateNotFound
+}
+
+func containsFold(s, substr string) bool {
+	return substr == "" || strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
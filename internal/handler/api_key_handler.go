@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type APIKeyHandler struct {
+	service *service.AuthService
+}
+
+func NewAPIKeyHandler(service *service.AuthService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+func (h *APIKeyHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /api-keys", authorize(authz, domain.PermAPIKeysManage, h.getAll))
+	mux.HandleFunc("POST /api-keys", authorize(authz, domain.PermAPIKeysManage, h.create))
+	mux.HandleFunc("DELETE /api-keys/{id}", authorize(authz, domain.PermAPIKeysManage, withID("API key", h.revoke)))
+}
+
+// GetAllAPIKeys godoc
+//
+//	@Summary		Get all API keys
+//	@Description	Get all API keys, including revoked ones. Keys are shown by prefix only.
+//	@Tags			api-keys
+//	@Produce		json
+//	@Success		200	{array}	domain.APIKey
+//	@Router			/api-keys [get]
+func (h *APIKeyHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.GetAPIKeys(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary		Create an API key
+//	@Description	Create an API key for a role (viewer by default). The key is only returned in this response.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			api_key	body		domain.APIKey	true	"API key name and role"
+//	@Success		201		{object}	domain.APIKey
+//	@Failure		400		{string}	string	"Invalid API key"
+//	@Router			/api-keys [post]
+func (h *APIKeyHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req domain.APIKey
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.CreateAPIKey(r.Context(), req.Name, req.Role)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary		Revoke an API key
+//	@Description	Revoke an API key; requests using it are rejected from then on
+//	@Tags			api-keys
+//	@Param			id	path		int	true	"API key ID"
+//	@Success		204	{string}	string	"No Content"
+//	@Failure		404	{string}	string	"API key not found"
+//	@Router			/api-keys/{id} [delete]
+func (h *APIKeyHandler) revoke(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.RevokeAPIKey(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	service *service.AuditService
+}
+
+func NewAuditHandler(service *service.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /audit", authorize(authz, domain.PermAuditRead, h.find))
+}
+
+// GetAuditLog godoc
+//
+//	@Summary		Get the audit log
+//	@Description	Get recorded changes, newest first. from and to are dates (YYYY-MM-DD) or RFC 3339 times; to is exclusive.
+//	@Tags			audit
+//	@Produce		json
+//	@Param			actor		query		string	false	"Actor"
+//	@Param			action		query		string	false	"Action"	Enums(create, update, delete)
+//	@Param			entity_type	query		string	false	"Entity type, e.g. product"
+//	@Param			entity_id	query		int		false	"Entity ID"
+//	@Param			from		query		string	false	"From"
+//	@Param			to			query		string	false	"To"
+//	@Param			limit		query		int		false	"Maximum entries (default 100, at most 1000)"
+//	@Success		200			{array}		domain.AuditEntry
+//	@Failure		400			{string}	string	"Invalid filter"
+//	@Router			/audit [get]
+func (h *AuditHandler) find(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := domain.AuditFilter{
+		Actor:      q.Get("actor"),
+		Action:     domain.AuditAction(q.Get("action")),
+		EntityType: q.Get("entity_type"),
+	}
+
+	var err error
+	if s := q.Get("entity_id"); s != "" {
+		if filter.EntityID, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "Invalid entity_id", http.StatusBadRequest)
+			return
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		if filter.Limit, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	if filter.From, err = parseAuditTime(q.Get("from")); err != nil {
+		http.Error(w, "Invalid from (YYYY-MM-DD or RFC 3339)", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseAuditTime(q.Get("to")); err != nil {
+		http.Error(w, "Invalid to (YYYY-MM-DD or RFC 3339)", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.Find(r.Context(), filter)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidAuditFilter) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+func parseAuditTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
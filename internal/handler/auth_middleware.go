@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/logging"
+	"cateogry-api/internal/service"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// RequireAuth rejects requests that carry neither a valid X-API-Key header
+// nor a valid "Authorization: Bearer" JWT, and stores the caller in the
+// request context for the handlers behind it.
+func RequireAuth(auth *service.AuthService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var principal *domain.Principal
+		var err error
+		if key := r.Header.Get("X-API-Key"); key != "" {
+			principal, err = auth.AuthenticateAPIKey(r.Context(), key)
+		} else if token, ok := bearerToken(r); ok {
+			principal, err = auth.AuthenticateJWT(token)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			return
+		}
+
+		if err != nil {
+			if errors.Is(err, service.ErrUnauthenticated) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx := service.WithPrincipal(r.Context(), principal)
+		ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("actor", principal.Subject))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
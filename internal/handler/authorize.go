@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"net/http"
+)
+
+// authorize guards a route with the permission it needs. The caller is the
+// principal stored by RequireAuth. A nil authz turns the check off, for
+// deployments without authentication.
+func authorize(authz *service.Authorizer, perm domain.Permission, next http.HandlerFunc) http.HandlerFunc {
+	if authz == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := service.PrincipalFromContext(r.Context())
+		if principal == nil {
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+			return
+		}
+		if !authz.Allowed(principal, perm) {
+			http.Error(w, "Forbidden: requires permission "+string(perm), http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
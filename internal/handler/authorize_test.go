@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type registerFunc func(*http.ServeMux, *service.Authorizer)
+
+// serveAs sends a request as a caller with role through the routes added by
+// register and reports whether it got past authorization. The handlers have
+// no services, so a request that reaches one usually panics; that counts as
+// reached too.
+func serveAs(t *testing.T, register registerFunc, authz *service.Authorizer, principal *domain.Principal, method, path string) (status int, reached bool) {
+	t.Helper()
+	mux := http.NewServeMux()
+	register(mux, authz)
+
+	req := httptest.NewRequest(method, path, nil)
+	if principal != nil {
+		req = req.WithContext(service.WithPrincipal(req.Context(), principal))
+	}
+	rec := httptest.NewRecorder()
+
+	defer func() {
+		if recover() != nil {
+			status, reached = 0, true
+		}
+	}()
+	mux.ServeHTTP(rec, req)
+	return rec.Code, rec.Code != http.StatusForbidden && rec.Code != http.StatusUnauthorized
+}
+
+func TestRegisterRoutesEnforcePermissions(t *testing.T) {
+	tests := []struct {
+		handler  string
+		register registerFunc
+		method   string
+		path     string
+		perm     domain.Permission
+	}{
+		{"category", (&CategoryHandler{}).RegisterRoutes, http.MethodGet, "/categories", domain.PermCategoriesRead},
+		{"category", (&CategoryHandler{}).RegisterRoutes, http.MethodPost, "/categories", domain.PermCategoriesWrite},
+		{"category", (&CategoryHandler{}).RegisterRoutes, http.MethodGet, "/categories/1", domain.PermCategoriesRead},
+		{"category", (&CategoryHandler{}).RegisterRoutes, http.MethodPut, "/categories/1", domain.PermCategoriesWrite},
+		{"category", (&CategoryHandler{}).RegisterRoutes, http.MethodDelete, "/categories/1", domain.PermCategoriesWrite},
+
+		{"product", (&ProductHandler{}).RegisterRoutes, http.MethodGet, "/products", domain.PermProductsRead},
+		{"product", (&ProductHandler{}).RegisterRoutes, http.MethodPost, "/products", domain.PermProductsWrite},
+		{"product", (&ProductHandler{}).RegisterRoutes, http.MethodGet, "/products/1", domain.PermProductsRead},
+		{"product", (&ProductHandler{}).RegisterRoutes, http.MethodPut, "/products/1", domain.PermProductsWrite},
+		{"product", (&ProductHandler{}).RegisterRoutes, http.MethodDelete, "/products/1", domain.PermProductsWrite},
+		{"product", (&ProductHandler{}).RegisterRoutes, http.MethodGet, "/categories/1/products", domain.PermProductsRead},
+
+		{"location", (&LocationHandler{}).RegisterRoutes, http.MethodGet, "/locations", domain.PermLocationsRead},
+		{"location", (&LocationHandler{}).RegisterRoutes, http.MethodPost, "/locations", domain.PermLocationsWrite},
+		{"location", (&LocationHandler{}).RegisterRoutes, http.MethodGet, "/locations/1", domain.PermLocationsRead},
+		{"location", (&LocationHandler{}).RegisterRoutes, http.MethodDelete, "/locations/1", domain.PermLocationsWrite},
+		{"location", (&LocationHandler{}).RegisterRoutes, http.MethodGet, "/stock-transfers", domain.PermLocationsRead},
+		{"location", (&LocationHandler{}).RegisterRoutes, http.MethodPost, "/stock-transfers", domain.PermLocationsWrite},
+
+		{"supplier", (&SupplierHandler{}).RegisterRoutes, http.MethodGet, "/suppliers", domain.PermSuppliersRead},
+		{"supplier", (&SupplierHandler{}).RegisterRoutes, http.MethodPost, "/suppliers", domain.PermSuppliersWrite},
+		{"supplier", (&SupplierHandler{}).RegisterRoutes, http.MethodPut, "/suppliers/1", domain.PermSuppliersWrite},
+
+		{"purchase order", (&PurchaseOrderHandler{}).RegisterRoutes, http.MethodGet, "/purchase-orders", domain.PermPurchaseOrdersRead},
+		{"purchase order", (&PurchaseOrderHandler{}).RegisterRoutes, http.MethodPost, "/purchase-orders", domain.PermPurchaseOrdersWrite},
+		{"purchase order", (&PurchaseOrderHandler{}).RegisterRoutes, http.MethodGet, "/purchase-orders/1", domain.PermPurchaseOrdersRead},
+		{"purchase order", (&PurchaseOrderHandler{}).RegisterRoutes, http.MethodPost, "/purchase-orders/1/receive", domain.PermPurchaseOrdersWrite},
+
+		{"promotion", (&PromotionHandler{}).RegisterRoutes, http.MethodGet, "/promotions", domain.PermPromotionsRead},
+		{"promotion", (&PromotionHandler{}).RegisterRoutes, http.MethodPost, "/promotions", domain.PermPromotionsWrite},
+		{"promotion", (&PromotionHandler{}).RegisterRoutes, http.MethodDelete, "/promotions/1", domain.PermPromotionsWrite},
+
+		{"tax", (&TaxHandler{}).RegisterRoutes, http.MethodGet, "/tax-rates", domain.PermTaxesRead},
+		{"tax", (&TaxHandler{}).RegisterRoutes, http.MethodPost, "/tax-rates", domain.PermTaxesWrite},
+		{"tax", (&TaxHandler{}).RegisterRoutes, http.MethodPut, "/tax-rates/1", domain.PermTaxesWrite},
+
+		{"transaction", (&TransactionHandler{}).RegisterRoutes, http.MethodPost, "/checkout", domain.PermCheckoutCreate},
+		{"transaction", (&TransactionHandler{}).RegisterRoutes, http.MethodGet, "/transactions/1", domain.PermTransactionsRead},
+		{"transaction", (&TransactionHandler{}).RegisterRoutes, http.MethodGet, "/report", domain.PermReportsRead},
+		{"transaction", (&TransactionHandler{}).RegisterRoutes, http.MethodGet, "/report/hari-ini", domain.PermReportsRead},
+
+		{"cart", (&CartHandler{}).RegisterRoutes, http.MethodPost, "/carts", domain.PermCheckoutCreate},
+		{"cart", (&CartHandler{}).RegisterRoutes, http.MethodGet, "/carts/1", domain.PermCheckoutCreate},
+		{"cart", (&CartHandler{}).RegisterRoutes, http.MethodPut, "/carts/1/items/2", domain.PermCheckoutCreate},
+		{"cart", (&CartHandler{}).RegisterRoutes, http.MethodPost, "/carts/1/checkout", domain.PermCheckoutCreate},
+
+		{"reservation", (&ReservationHandler{}).RegisterRoutes, http.MethodPost, "/reservations", domain.PermCheckoutCreate},
+		{"reservation", (&ReservationHandler{}).RegisterRoutes, http.MethodDelete, "/reservations/1", domain.PermCheckoutCreate},
+
+		{"api key", (&APIKeyHandler{}).RegisterRoutes, http.MethodGet, "/api-keys", domain.PermAPIKeysManage},
+		{"api key", (&APIKeyHandler{}).RegisterRoutes, http.MethodPost, "/api-keys", domain.PermAPIKeysManage},
+		{"api key", (&APIKeyHandler{}).RegisterRoutes, http.MethodDelete, "/api-keys/1", domain.PermAPIKeysManage},
+
+		{"audit", (&AuditHandler{}).RegisterRoutes, http.MethodGet, "/audit", domain.PermAuditRead},
+
+		{"health", (&HealthHandler{}).RegisterRoutes, http.MethodGet, "/health", domain.PermReportsRead},
+	}
+
+	authz := service.NewAuthorizer(service.DefaultRolePermissions())
+	roles := []domain.Role{domain.RoleAdmin, domain.RoleCashier, domain.RoleViewer, "unknown"}
+	for _, tt := range tests {
+		for _, role := range roles {
+			principal := &domain.Principal{Subject: "test", Role: role}
+			want := authz.Allowed(principal, tt.perm)
+			status, reached := serveAs(t, tt.register, authz, principal, tt.method, tt.path)
+			if reached != want {
+				t.Errorf("%s handler: %s %s as %s: reached = %v (status %d), want %v", tt.handler, tt.method, tt.path, role, reached, status, want)
+			}
+			if !want && status != http.StatusForbidden {
+				t.Errorf("%s handler: %s %s as %s: status = %d, want 403", tt.handler, tt.method, tt.path, role, status)
+			}
+		}
+	}
+}
+
+func TestCashierPermissions(t *testing.T) {
+	authz := service.NewAuthorizer(service.DefaultRolePermissions())
+	cashier := &domain.Principal{Subject: "till-1", Role: domain.RoleCashier}
+
+	if _, reached := serveAs(t, (&TransactionHandler{}).RegisterRoutes, authz, cashier, http.MethodPost, "/checkout"); !reached {
+		t.Error("cashier cannot POST /checkout")
+	}
+	if status, _ := serveAs(t, (&CategoryHandler{}).RegisterRoutes, authz, cashier, http.MethodDelete, "/categories/1"); status != http.StatusForbidden {
+		t.Errorf("cashier DELETE /categories/1: status = %d, want 403", status)
+	}
+	if status, _ := serveAs(t, (&TransactionHandler{}).RegisterRoutes, authz, cashier, http.MethodGet, "/report"); status != http.StatusForbidden {
+		t.Errorf("cashier GET /report: status = %d, want 403", status)
+	}
+}
+
+func TestAuthorizeWithoutPrincipal(t *testing.T) {
+	authz := service.NewAuthorizer(service.DefaultRolePermissions())
+	if status, _ := serveAs(t, (&CategoryHandler{}).RegisterRoutes, authz, nil, http.MethodGet, "/categories"); status != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", status)
+	}
+}
+
+func TestAuthorizeDisabled(t *testing.T) {
+	if _, reached := serveAs(t, (&CategoryHandler{}).RegisterRoutes, nil, nil, http.MethodDelete, "/categories/1"); !reached {
+		t.Error("request was stopped although authorization is off")
+	}
+}
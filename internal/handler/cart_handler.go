@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type CartHandler struct {
+	service *service.CartService
+}
+
+func NewCartHandler(service *service.CartService) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+func (h *CartHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("POST /carts", authorize(authz, domain.PermCheckoutCreate, h.create))
+	mux.HandleFunc("GET /carts/{id}", authorize(authz, domain.PermCheckoutCreate, withID("cart", h.getByID)))
+	mux.HandleFunc("DELETE /carts/{id}", authorize(authz, domain.PermCheckoutCreate, withID("cart", h.delete)))
+	mux.HandleFunc("POST /carts/{id}/items", authorize(authz, domain.PermCheckoutCreate, withID("cart", h.addItem)))
+	mux.HandleFunc("PUT /carts/{id}/items/{product_id}", authorize(authz, domain.PermCheckoutCreate, withCartItem(h.updateItem)))
+	mux.HandleFunc("DELETE /carts/{id}/items/{product_id}", authorize(authz, domain.PermCheckoutCreate, withCartItem(h.removeItem)))
+	mux.HandleFunc("POST /carts/{id}/checkout", authorize(authz, domain.PermCheckoutCreate, withID("cart", h.checkout)))
+}
+
+// withCartItem passes the {id} and {product_id} wildcards of the route to next.
+func withCartItem(next func(http.ResponseWriter, *http.Request, int, int)) http.HandlerFunc {
+	return withID("cart", func(w http.ResponseWriter, r *http.Request, id int) {
+		productID, err := strconv.Atoi(r.PathValue("product_id"))
+		if err != nil {
+			http.Error(w, "Invalid product ID", http.StatusBadRequest)
+			return
+		}
+		next(w, r, id, productID)
+	})
+}
+
+// cartErrorStatus maps validation errors to 400 and everything else to 404,
+// since the remaining failures are missing carts, items or products.
+func cartErrorStatus(err error) int {
+	if errors.Is(err, service.ErrInvalidCart) {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotFound
+}
+
+func writeCart(w http.ResponseWriter, status int, cart *domain.Cart) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(cart)
+}
+
+// CreateCart godoc
+//
+//	@Summary		Create a cart
+//	@Description	Create an empty cart that can be parked and checked out later
+//	@Tags			carts
+//	@Accept			json
+//	@Produce		json
+//	@Success		201	{object}	domain.Cart
+//	@Router			/carts [post]
+func (h *CartHandler) create(w http.ResponseWriter, r *http.Request) {
+	cart, err := h.service.Create(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeCart(w, http.StatusCreated, cart)
+}
+
+// GetCart godoc
+//
+//	@Summary		Get a cart
+//	@Description	Get a cart with live prices and stock availability
+//	@Tags			carts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Cart ID"
+//	@Success		200	{object}	domain.Cart
+//	@Failure		404	{string}	string	"Cart not found"
+//	@Router			/carts/{id} [get]
+func (h *CartHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	cart, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeCart(w, http.StatusOK, cart)
+}
+
+func (h *CartHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddCartItem godoc
+//
+//	@Summary		Add an item to a cart
+//	@Description	Add a product to a cart, increasing the quantity if it is already there
+//	@Tags			carts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Cart ID"
+//	@Param			item	body		domain.CheckoutItem	true	"Item"
+//	@Success		200		{object}	domain.Cart
+//	@Failure		400		{string}	string	"Invalid item"
+//	@Failure		404		{string}	string	"Cart or product not found"
+//	@Router			/carts/{id}/items [post]
+func (h *CartHandler) addItem(w http.ResponseWriter, r *http.Request, id int) {
+	var item domain.CheckoutItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	cart, err := h.service.AddItem(r.Context(), id, item)
+	if err != nil {
+		http.Error(w, err.Error(), cartErrorStatus(err))
+		return
+	}
+	writeCart(w, http.StatusOK, cart)
+}
+
+func (h *CartHandler) updateItem(w http.ResponseWriter, r *http.Request, id, productID int) {
+	var item domain.CheckoutItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	cart, err := h.service.UpdateItem(r.Context(), id, productID, item.Quantity)
+	if err != nil {
+		http.Error(w, err.Error(), cartErrorStatus(err))
+		return
+	}
+	writeCart(w, http.StatusOK, cart)
+}
+
+func (h *CartHandler) removeItem(w http.ResponseWriter, r *http.Request, id, productID int) {
+	cart, err := h.service.RemoveItem(r.Context(), id, productID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeCart(w, http.StatusOK, cart)
+}
+
+// CheckoutCart godoc
+//
+//	@Summary		Check out a cart
+//	@Description	Commit the cart as a transaction, applying promotions, tax and payments
+//	@Tags			carts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int							true	"Cart ID"
+//	@Param			checkout	body		domain.CartCheckoutRequest	false	"Promo codes and payments"
+//	@Success		200			{object}	domain.Transaction
+//	@Failure		400			{string}	string	"Invalid cart, promo code or payment"
+//	@Failure		404			{string}	string	"Cart, product or location not found"
+//	@Failure		409			{string}	string	"Insufficient stock"
+//	@Router			/carts/{id}/checkout [post]
+func (h *CartHandler) checkout(w http.ResponseWriter, r *http.Request, id int) {
+	var req domain.CartCheckoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	transaction, err := h.service.Checkout(r.Context(), id, req)
+	if errors.Is(err, service.ErrInvalidCart) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrCartNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeError(w, err, checkoutErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transaction)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSSettings lists what browsers on other origins may do. An origin of "*"
+// allows any origin.
+type CORSSettings struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration
+}
+
+// CORS adds the CORS response headers for allowed origins and answers their
+// preflight requests itself, before authentication, since browsers send
+// those without credentials. With no allowed origins it adds nothing.
+func CORS(s CORSSettings, next http.Handler) http.Handler {
+	if len(s.AllowedOrigins) == 0 {
+		return next
+	}
+	anyOrigin := slices.Contains(s.AllowedOrigins, "*")
+	methods := strings.Join(s.AllowedMethods, ", ")
+	headers := strings.Join(s.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(s.MaxAge.Seconds()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		if !anyOrigin && !slices.Contains(s.AllowedOrigins, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+			w.Header().Set("Access-Control-Max-Age", maxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, "+errorCodeHeader)
+		next.ServeHTTP(w, r)
+	})
+}
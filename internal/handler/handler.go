@@ -5,8 +5,6 @@ import (
 	"cateogry-api/internal/service"
 	"encoding/json"
 	"net/http"
-	"strconv"
-	"strings"
 )
 
 type CategoryHandler struct {
@@ -17,40 +15,12 @@ func NewCategoryHandler(s *service.CategoryService) *CategoryHandler {
 	return &CategoryHandler{service: s}
 }
 
-func (h *CategoryHandler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/categories", h.categoriesHandler)
-	mux.HandleFunc("/categories/", h.categoryHandler)
-}
-
-func (h *CategoryHandler) categoriesHandler(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.getCategories(w, r)
-	case http.MethodPost:
-		h.createCategory(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *CategoryHandler) categoryHandler(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/categories/")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
-		return
-	}
-
-	switch r.Method {
-	case http.MethodGet:
-		h.getCategoryByID(w, r, id)
-	case http.MethodPut:
-		h.updateCategory(w, r, id)
-	case http.MethodDelete:
-		h.deleteCategory(w, r, id)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+func (h *CategoryHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /categories", authorize(authz, domain.PermCategoriesRead, h.getCategories))
+	mux.HandleFunc("POST /categories", authorize(authz, domain.PermCategoriesWrite, h.createCategory))
+	mux.HandleFunc("GET /categories/{id}", authorize(authz, domain.PermCategoriesRead, withID("category", h.getCategoryByID)))
+	mux.HandleFunc("PUT /categories/{id}", authorize(authz, domain.PermCategoriesWrite, withID("category", h.updateCategory)))
+	mux.HandleFunc("DELETE /categories/{id}", authorize(authz, domain.PermCategoriesWrite, withID("category", h.deleteCategory)))
 }
 
 // GetCategories godoc
@@ -63,7 +33,7 @@ func (h *CategoryHandler) categoryHandler(w http.ResponseWriter, r *http.Request
 //	@Success		200	{array}	domain.Category
 //	@Router			/categories [get]
 func (h *CategoryHandler) getCategories(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.service.GetAllCategories()
+	categories, err := h.service.GetAllCategories(r.Context())
 	if err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -90,7 +60,7 @@ func (h *CategoryHandler) createCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	createdCategory, err := h.service.CreateCategory(newCategory)
+	createdCategory, err := h.service.CreateCategory(r.Context(), newCategory)
 	if err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
@@ -113,7 +83,7 @@ func (h *CategoryHandler) createCategory(w http.ResponseWriter, r *http.Request)
 //	@Failure		404	{string}	string	"Category not found"
 //	@Router			/categories/{id} [get]
 func (h *CategoryHandler) getCategoryByID(w http.ResponseWriter, r *http.Request, id int) {
-	category, err := h.service.GetCategoryByID(id)
+	category, err := h.service.GetCategoryByID(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Category not found", http.StatusNotFound)
 		return
@@ -130,7 +100,7 @@ func (h *CategoryHandler) updateCategory(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	updatedCategory, err := h.service.UpdateCategory(id, updatedData)
+	updatedCategory, err := h.service.UpdateCategory(r.Context(), id, updatedData)
 	if err != nil {
 		http.Error(w, "Category not found", http.StatusNotFound)
 		return
@@ -141,7 +111,7 @@ func (h *CategoryHandler) updateCategory(w http.ResponseWriter, r *http.Request,
 }
 
 func (h *CategoryHandler) deleteCategory(w http.ResponseWriter, r *http.Request, id int) {
-	err := h.service.DeleteCategory(id)
+	err := h.service.DeleteCategory(r.Context(), id)
 	if err != nil {
 		http.Error(w, "Category not found", http.StatusNotFound)
 		return
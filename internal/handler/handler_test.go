@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// request sends method path with body, which may be empty, through h.
+func request(t *testing.T, h http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, path, nil)
+	} else {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeBody decodes the JSON response of rec into v.
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+}
+
+// statusTest is a request and the status it must get.
+type statusTest struct {
+	method, path, body string
+	want               int
+}
+
+func checkStatuses(t *testing.T, h http.Handler, tests []statusTest) {
+	t.Helper()
+	for _, tt := range tests {
+		if rec := request(t, h, tt.method, tt.path, tt.body); rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d (%s)", tt.method, tt.path, rec.Code, tt.want, strings.TrimSpace(rec.Body.String()))
+		}
+	}
+}
+
+func newCategoryMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	svc := service.NewCategoryService(repository.NewInMemoryCategoryRepository(), nil)
+	NewCategoryHandler(svc).RegisterRoutes(mux, nil)
+	return mux
+}
+
+func TestCategoryHandler(t *testing.T) {
+	mux := newCategoryMux()
+
+	rec := request(t, mux, http.MethodPost, "/categories", `{"name":"Toys","description":"For kids"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /categories: status = %d, want 201", rec.Code)
+	}
+	var created domain.Category
+	decodeBody(t, rec, &created)
+	if created.ID == 0 || created.Name != "Toys" {
+		t.Fatalf("POST /categories = %+v", created)
+	}
+	path := "/categories/" + strconv.Itoa(created.ID)
+
+	rec = request(t, mux, http.MethodGet, "/categories", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /categories: status = %d, want 200", rec.Code)
+	}
+	var all []domain.Category
+	decodeBody(t, rec, &all)
+	if !containsCategory(all, created.ID) {
+		t.Errorf("GET /categories does not list category %d", created.ID)
+	}
+
+	rec = request(t, mux, http.MethodPut, path, `{"name":"Games","description":"For everyone"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT %s: status = %d, want 200", path, rec.Code)
+	}
+	var got domain.Category
+	decodeBody(t, request(t, mux, http.MethodGet, path, ""), &got)
+	if got.Name != "Games" {
+		t.Errorf("GET %s after PUT: name = %q, want Games", path, got.Name)
+	}
+
+	if rec := request(t, mux, http.MethodDelete, path, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE %s: status = %d, want 204", path, rec.Code)
+	}
+	checkStatuses(t, mux, []statusTest{
+		{http.MethodGet, path, "", http.StatusNotFound},
+		{http.MethodPut, path, `{"name":"Gone"}`, http.StatusNotFound},
+		{http.MethodDelete, path, "", http.StatusNotFound},
+	})
+}
+
+func TestCategoryHandlerBadRequests(t *testing.T) {
+	checkStatuses(t, newCategoryMux(), []statusTest{
+		{http.MethodPost, "/categories", `{"name":`, http.StatusBadRequest},
+		{http.MethodPut, "/categories/1", `not json`, http.StatusBadRequest},
+		{http.MethodGet, "/categories/abc", "", http.StatusBadRequest},
+		{http.MethodPatch, "/categories", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/categories/1", `{}`, http.StatusMethodNotAllowed},
+		{http.MethodGet, "/categories/", "", http.StatusNotFound},
+		{http.MethodGet, "/categories/1/foo", "", http.StatusNotFound},
+	})
+}
+
+func TestRoutesMethodNotAllowed(t *testing.T) {
+	tests := []struct {
+		register  registerFunc
+		method    string
+		path      string
+		wantAllow string
+	}{
+		{(&CategoryHandler{}).RegisterRoutes, http.MethodPatch, "/categories", "GET, HEAD, POST"},
+		{(&CategoryHandler{}).RegisterRoutes, http.MethodPost, "/categories/1", "DELETE, GET, HEAD, PUT"},
+		{(&ProductHandler{}).RegisterRoutes, http.MethodDelete, "/products", "GET, HEAD, POST"},
+		{(&ProductHandler{}).RegisterRoutes, http.MethodPost, "/categories/1/products", "GET, HEAD"},
+		{(&TransactionHandler{}).RegisterRoutes, http.MethodGet, "/checkout", "POST"},
+		{(&TransactionHandler{}).RegisterRoutes, http.MethodDelete, "/transactions/1", "GET, HEAD"},
+		{(&TransactionHandler{}).RegisterRoutes, http.MethodPost, "/report", "GET, HEAD"},
+		{(&CartHandler{}).RegisterRoutes, http.MethodGet, "/carts/1/items/2", "DELETE, PUT"},
+		{(&PurchaseOrderHandler{}).RegisterRoutes, http.MethodGet, "/purchase-orders/1/receive", "POST"},
+		{(&APIKeyHandler{}).RegisterRoutes, http.MethodGet, "/api-keys/1", "DELETE"},
+	}
+	for _, tt := range tests {
+		mux := http.NewServeMux()
+		tt.register(mux, nil)
+		rec := request(t, mux, tt.method, tt.path, "")
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: status = %d, want 405", tt.method, tt.path, rec.Code)
+		}
+		if allow := rec.Header().Get("Allow"); allow != tt.wantAllow {
+			t.Errorf("%s %s: Allow = %q, want %q", tt.method, tt.path, allow, tt.wantAllow)
+		}
+	}
+}
+
+func TestRoutesNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	for _, register := range []registerFunc{
+		(&CategoryHandler{}).RegisterRoutes,
+		(&ProductHandler{}).RegisterRoutes,
+		(&TransactionHandler{}).RegisterRoutes,
+		(&CartHandler{}).RegisterRoutes,
+	} {
+		register(mux, nil)
+	}
+	for _, path := range []string{
+		"/categories/1/foo",
+		"/categories/1/products/2",
+		"/products/",
+		"/products/1/",
+		"/transactions",
+		"/report/kemarin",
+		"/carts/1/items/2/3",
+		"/unknown",
+	} {
+		if rec := request(t, mux, http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s: status = %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func containsCategory(categories []domain.Category, id int) bool {
+	for _, c := range categories {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -1,27 +1,144 @@
 package handler
 
 import (
+	"cateogry-api/database"
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/logging"
+	"cateogry-api/internal/service"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// healthCheckTimeout bounds each dependency check so a hung database cannot
+// hang the probes.
+const healthCheckTimeout = 2 * time.Second
+
+var errNoDatabase = errors.New("database connection was not opened")
+
 type HealthHandler struct {
-	db *sql.DB
+	db       *sql.DB
+	draining atomic.Bool
+
+	mu   sync.Mutex
+	jobs map[string]JobHealth
 }
 
 func NewHealthHandler(db *sql.DB) *HealthHandler {
-	return &HealthHandler{db: db}
+	return &HealthHandler{db: db, jobs: make(map[string]JobHealth)}
+}
+
+// RegisterRoutes registers the detailed report, which names the database,
+// migrations and jobs, for authenticated callers. The probes are meant to be
+// served without authentication with RegisterProbes.
+func (h *HealthHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /health", authorize(authz, domain.PermReportsRead, h.Check))
+}
+
+// RegisterProbes registers the liveness and readiness probes under prefix.
+// They report a status only, no dependency details.
+func (h *HealthHandler) RegisterProbes(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc("GET "+prefix+"/health/live", h.Live)
+	mux.HandleFunc("GET "+prefix+"/health/ready", h.Ready)
 }
 
 type HealthResponse struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	Status     string               `json:"status"`
+	Error      string               `json:"error,omitempty"`
+	Database   *DatabaseHealth      `json:"database,omitempty"`
+	Migrations *MigrationHealth     `json:"migrations,omitempty"`
+	Jobs       map[string]JobHealth `json:"jobs,omitempty"`
+}
+
+type DatabaseHealth struct {
+	Status         string  `json:"status"`
+	Error          string  `json:"error,omitempty"`
+	LatencyMS      float64 `json:"latency_ms"`
+	OpenConns      int     `json:"open_connections"`
+	InUse          int     `json:"in_use"`
+	Idle           int     `json:"idle"`
+	MaxOpenConns   int     `json:"max_open_connections"`
+	WaitCount      int64   `json:"wait_count"`
+	WaitDurationMS float64 `json:"wait_duration_ms"`
+}
+
+type MigrationHealth struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Version int    `json:"version"`
+	Latest  int    `json:"latest"`
+}
+
+type JobHealth struct {
+	Status      string     `json:"status"`
+	LastRun     time.Time  `json:"last_run"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// JobFinished records the outcome of a background job run for the detailed
+// report.
+func (h *HealthHandler) JobFinished(job string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	j := h.jobs[job]
+	j.LastRun = now
+	j.Status, j.LastError = "up", ""
+	if err != nil {
+		j.Status, j.LastError = "down", err.Error()
+	} else {
+		j.LastSuccess = &now
+	}
+	h.jobs[job] = j
+}
+
+// Drain marks the server as shutting down, after which it reports not ready.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live godoc
+// @Summary      Liveness probe
+// @Description  Reports that the process is running; it does not check dependencies
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  HealthResponse
+// @Router       /health/live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, HealthResponse{Status: "alive"})
+}
+
+// Ready godoc
+// @Summary      Readiness probe
+// @Description  Reports whether the server can take traffic: the database answers and the server is not shutting down
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  HealthResponse
+// @Failure      503  {object}  HealthResponse
+// @Router       /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, HealthResponse{Status: "not ready", Error: "server is shutting down"})
+		return
+	}
+	if err := h.ping(r.Context()); err != nil {
+		logging.FromContext(r.Context()).Warn("Readiness check failed", "error", err)
+		writeHealth(w, http.StatusServiceUnavailable, HealthResponse{Status: "not ready", Error: "database is not reachable"})
+		return
+	}
+	writeHealth(w, http.StatusOK, HealthResponse{Status: "ready"})
 }
 
 // CheckHealth godoc
 // @Summary      Health Check
-// @Description  Check database connection status
+// @Description  Detailed report of the database (ping latency and pool statistics), schema migrations and background jobs. The status is unhealthy when the database is down and degraded when migrations are behind or a job last failed. Requires the reports:read permission.
 // @Tags         health
 // @Accept       json
 // @Produce      json
@@ -29,17 +146,89 @@ type HealthResponse struct {
 // @Failure      503  {object}  HealthResponse
 // @Router       /health [get]
 func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
-	err := h.db.Ping()
 	response := HealthResponse{Status: "healthy"}
 	statusCode := http.StatusOK
 
-	if err != nil {
+	response.Database = h.checkDatabase(r.Context())
+	if response.Database.Status != "up" {
+		response.Status = "unhealthy"
+		response.Error = response.Database.Error
+		statusCode = http.StatusServiceUnavailable
+	} else {
+		response.Migrations = h.checkMigrations(r.Context())
+		if response.Migrations.Status != "up" {
+			response.Status = "degraded"
+		}
+	}
+
+	h.mu.Lock()
+	if len(h.jobs) > 0 {
+		response.Jobs = make(map[string]JobHealth, len(h.jobs))
+		for name, j := range h.jobs {
+			response.Jobs[name] = j
+			if j.Status != "up" && response.Status == "healthy" {
+				response.Status = "degraded"
+			}
+		}
+	}
+	h.mu.Unlock()
+
+	if h.draining.Load() {
 		response.Status = "unhealthy"
-		response.Error = err.Error()
+		response.Error = "server is shutting down"
 		statusCode = http.StatusServiceUnavailable
 	}
+	writeHealth(w, statusCode, response)
+}
+
+func (h *HealthHandler) ping(ctx context.Context) error {
+	if h.db == nil {
+		return errNoDatabase
+	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return h.db.PingContext(ctx)
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) *DatabaseHealth {
+	start := time.Now()
+	err := h.ping(ctx)
+	check := &DatabaseHealth{Status: "up", LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		check.Status, check.Error = "down", err.Error()
+	}
+	if h.db != nil {
+		stats := h.db.Stats()
+		check.OpenConns = stats.OpenConnections
+		check.InUse = stats.InUse
+		check.Idle = stats.Idle
+		check.MaxOpenConns = stats.MaxOpenConnections
+		check.WaitCount = stats.WaitCount
+		check.WaitDurationMS = float64(stats.WaitDuration.Microseconds()) / 1000
+	}
+	return check
+}
+
+func (h *HealthHandler) checkMigrations(ctx context.Context) *MigrationHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	check := &MigrationHealth{Status: "up", Latest: database.LatestVersion()}
+	version, err := database.SchemaVersion(ctx, h.db)
+	if err != nil {
+		check.Status, check.Error = "down", err.Error()
+		return check
+	}
+	check.Version = version
+	if version < check.Latest {
+		check.Status = "behind"
+	}
+	return check
+}
 
+func writeHealth(w http.ResponseWriter, statusCode int, response HealthResponse) {
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(response)
 }
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"cateogry-api/database"
+	"cateogry-api/internal/testdb"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newHealthMux(h *HealthHandler) *http.ServeMux {
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux, nil)
+	h.RegisterProbes(mux, "")
+	return mux
+}
+
+func TestHealthHandlerWithoutDatabase(t *testing.T) {
+	h := NewHealthHandler(nil)
+	mux := newHealthMux(h)
+
+	rec := request(t, mux, http.MethodGet, "/health/live", "")
+	var live HealthResponse
+	decodeBody(t, rec, &live)
+	if rec.Code != http.StatusOK || live.Status != "alive" {
+		t.Errorf("GET /health/live = %d %q, want 200 alive", rec.Code, live.Status)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", cc)
+	}
+
+	rec = request(t, mux, http.MethodGet, "/health/ready", "")
+	var ready HealthResponse
+	decodeBody(t, rec, &ready)
+	if rec.Code != http.StatusServiceUnavailable || ready.Status != "not ready" {
+		t.Errorf("GET /health/ready = %d %+v, want 503 not ready", rec.Code, ready)
+	}
+	if strings.Contains(ready.Error, errNoDatabase.Error()) {
+		t.Errorf("GET /health/ready exposes the database error: %q", ready.Error)
+	}
+
+	rec = request(t, mux, http.MethodGet, "/health", "")
+	var check HealthResponse
+	decodeBody(t, rec, &check)
+	if rec.Code != http.StatusServiceUnavailable || check.Status != "unhealthy" {
+		t.Errorf("GET /health = %d %q, want 503 unhealthy", rec.Code, check.Status)
+	}
+	if check.Database == nil || check.Database.Status != "down" {
+		t.Errorf("GET /health database = %+v, want down", check.Database)
+	}
+	if check.Migrations != nil {
+		t.Errorf("GET /health checked migrations without a database: %+v", check.Migrations)
+	}
+}
+
+func TestHealthHandlerMethods(t *testing.T) {
+	checkStatuses(t, newHealthMux(NewHealthHandler(nil)), []statusTest{
+		{http.MethodPost, "/health", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/health/live", "", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/health/ready", "", http.StatusMethodNotAllowed},
+	})
+}
+
+func TestHealthHandlerJobs(t *testing.T) {
+	h := NewHealthHandler(nil)
+	mux := newHealthMux(h)
+
+	h.JobFinished("cleanup", nil)
+	h.JobFinished("reconnect", errors.New("connection refused"))
+
+	var check HealthResponse
+	decodeBody(t, request(t, mux, http.MethodGet, "/health", ""), &check)
+	cleanup, reconnect := check.Jobs["cleanup"], check.Jobs["reconnect"]
+	if cleanup.Status != "up" || cleanup.LastSuccess == nil {
+		t.Errorf("cleanup job = %+v, want up with a last success", cleanup)
+	}
+	if reconnect.Status != "down" || reconnect.LastError != "connection refused" || reconnect.LastSuccess != nil {
+		t.Errorf("reconnect job = %+v, want down with its error", reconnect)
+	}
+
+	// A later success clears the error but keeps the job listed
+	h.JobFinished("reconnect", nil)
+	decodeBody(t, request(t, mux, http.MethodGet, "/health", ""), &check)
+	if j := check.Jobs["reconnect"]; j.Status != "up" || j.LastError != "" {
+		t.Errorf("reconnect job after success = %+v, want up", j)
+	}
+}
+
+func TestHealthHandlerDrain(t *testing.T) {
+	h := NewHealthHandler(nil)
+	mux := newHealthMux(h)
+	h.Drain()
+
+	var ready HealthResponse
+	rec := request(t, mux, http.MethodGet, "/health/ready", "")
+	decodeBody(t, rec, &ready)
+	if rec.Code != http.StatusServiceUnavailable || ready.Error != "server is shutting down" {
+		t.Errorf("GET /health/ready while draining = %d %+v, want 503 shutting down", rec.Code, ready)
+	}
+	if rec := request(t, mux, http.MethodGet, "/health/live", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /health/live while draining: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestHealthHandlerPostgres(t *testing.T) {
+	db := testdb.Open(t)
+	mux := newHealthMux(NewHealthHandler(db))
+
+	if rec := request(t, mux, http.MethodGet, "/health/ready", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /health/ready: status = %d, want 200", rec.Code)
+	}
+
+	rec := request(t, mux, http.MethodGet, "/health", "")
+	var check HealthResponse
+	decodeBody(t, rec, &check)
+	if rec.Code != http.StatusOK || check.Status != "healthy" {
+		t.Errorf("GET /health = %d %q, want 200 healthy", rec.Code, check.Status)
+	}
+	if check.Migrations == nil || check.Migrations.Version != database.LatestVersion() {
+		t.Errorf("GET /health migrations = %+v, want version %d", check.Migrations, database.LatestVersion())
+	}
+
+	if err := database.MigrateDown(context.Background(), db, 1); err != nil {
+		t.Fatal(err)
+	}
+	decodeBody(t, request(t, mux, http.MethodGet, "/health", ""), &check)
+	if check.Status != "degraded" || check.Migrations.Status != "behind" {
+		t.Errorf("GET /health one migration behind = %q, migrations %+v; want degraded, behind", check.Status, check.Migrations)
+	}
+}
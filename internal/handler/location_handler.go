@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+type LocationHandler struct {
+	service *service.LocationService
+}
+
+func NewLocationHandler(service *service.LocationService) *LocationHandler {
+	return &LocationHandler{service: service}
+}
+
+func (h *LocationHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /locations", authorize(authz, domain.PermLocationsRead, h.getAll))
+	mux.HandleFunc("POST /locations", authorize(authz, domain.PermLocationsWrite, h.create))
+	mux.HandleFunc("GET /locations/{id}", authorize(authz, domain.PermLocationsRead, withID("location", h.getByID)))
+	mux.HandleFunc("PUT /locations/{id}", authorize(authz, domain.PermLocationsWrite, withID("location", h.update)))
+	mux.HandleFunc("DELETE /locations/{id}", authorize(authz, domain.PermLocationsWrite, withID("location", h.delete)))
+	mux.HandleFunc("GET /stock-transfers", authorize(authz, domain.PermLocationsRead, h.getTransfers))
+	mux.HandleFunc("POST /stock-transfers", authorize(authz, domain.PermLocationsWrite, h.transfer))
+}
+
+// locationErrorStatus maps validation errors to 400, unknown locations to 404
+// and everything else to fallback.
+func locationErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidLocation):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrLocationNotFound):
+		return http.StatusNotFound
+	default:
+		return fallback
+	}
+}
+
+// transferErrorStatus maps validation errors to 400, unknown locations and
+// products to 404, not enough stock to 409 and everything else to 500.
+func transferErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInsufficientStock):
+		return http.StatusConflict
+	default:
+		return locationErrorStatus(err, http.StatusInternalServerError)
+	}
+}
+
+// GetAllLocations godoc
+//
+//	@Summary		Get all locations
+//	@Description	Get all stores and warehouses
+//	@Tags			locations
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}	domain.Location
+//	@Router			/locations [get]
+func (h *LocationHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.service.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(locations)
+}
+
+// GetLocationByID godoc
+//
+//	@Summary		Get a location by ID
+//	@Description	Get a location by ID
+//	@Tags			locations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Location ID"
+//	@Success		200	{object}	domain.Location
+//	@Failure		404	{string}	string	"Location not found"
+//	@Router			/locations/{id} [get]
+func (h *LocationHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	location, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(location)
+}
+
+// CreateLocation godoc
+//
+//	@Summary		Create a new location
+//	@Description	Create a store or warehouse
+//	@Tags			locations
+//	@Accept			json
+//	@Produce		json
+//	@Param			location	body		domain.Location	true	"Location Data"
+//	@Success		201			{object}	domain.Location
+//	@Failure		400			{string}	string	"Invalid location"
+//	@Router			/locations [post]
+func (h *LocationHandler) create(w http.ResponseWriter, r *http.Request) {
+	var location domain.Location
+	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	createdLocation, err := h.service.Create(r.Context(), location)
+	if err != nil {
+		http.Error(w, err.Error(), locationErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(createdLocation)
+}
+
+func (h *LocationHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var location domain.Location
+	if err := json.NewDecoder(r.Body).Decode(&location); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	updatedLocation, err := h.service.Update(r.Context(), id, location)
+	if err != nil {
+		http.Error(w, err.Error(), locationErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedLocation)
+}
+
+func (h *LocationHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), locationErrorStatus(err, http.StatusConflict))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetStockTransfers godoc
+//
+//	@Summary		Get stock transfers
+//	@Description	Get stock transfers, newest first, optionally for one product
+//	@Tags			locations
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	query	int	false	"Product ID"
+//	@Success		200			{array}	domain.StockTransfer
+//	@Router			/stock-transfers [get]
+func (h *LocationHandler) getTransfers(w http.ResponseWriter, r *http.Request) {
+	productID := 0
+	if s := r.URL.Query().Get("product_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "Invalid product ID", http.StatusBadRequest)
+			return
+		}
+		productID = id
+	}
+
+	transfers, err := h.service.GetTransfers(r.Context(), productID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
+
+// CreateStockTransfer godoc
+//
+//	@Summary		Transfer stock between locations
+//	@Description	Move stock of one product from one location to another
+//	@Tags			locations
+//	@Accept			json
+//	@Produce		json
+//	@Param			transfer	body		domain.StockTransfer	true	"Transfer Data"
+//	@Success		201			{object}	domain.StockTransfer
+//	@Failure		400			{string}	string	"Invalid transfer"
+//	@Failure		404			{string}	string	"Location or product not found"
+//	@Failure		409			{string}	string	"Not enough stock"
+//	@Router			/stock-transfers [post]
+func (h *LocationHandler) transfer(w http.ResponseWriter, r *http.Request) {
+	var transfer domain.StockTransfer
+	if err := json.NewDecoder(r.Body).Decode(&transfer); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Transfer(r.Context(), transfer)
+	if err != nil {
+		writeError(w, err, transferErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
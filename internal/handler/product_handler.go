@@ -4,8 +4,8 @@ import (
 	"cateogry-api/internal/domain"
 	"cateogry-api/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
 )
 
 type ProductHandler struct {
@@ -16,40 +16,13 @@ func NewProductHandler(service *service.ProductService) *ProductHandler {
 	return &ProductHandler{service: service}
 }
 
-func (h *ProductHandler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/products", h.handleProducts)
-	mux.HandleFunc("/products/", h.handleProductByID)
-}
-
-func (h *ProductHandler) handleProducts(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.getAll(w, r)
-	case http.MethodPost:
-		h.create(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *ProductHandler) handleProductByID(w http.ResponseWriter, r *http.Request) {
-	idStr := r.URL.Path[len("/products/"):]
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
-		return
-	}
-
-	switch r.Method {
-	case http.MethodGet:
-		h.getByID(w, r, id)
-	case http.MethodPut:
-		h.update(w, r, id)
-	case http.MethodDelete:
-		h.delete(w, r, id)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+func (h *ProductHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /products", authorize(authz, domain.PermProductsRead, h.getAll))
+	mux.HandleFunc("POST /products", authorize(authz, domain.PermProductsWrite, h.create))
+	mux.HandleFunc("GET /products/{id}", authorize(authz, domain.PermProductsRead, withID("product", h.getByID)))
+	mux.HandleFunc("PUT /products/{id}", authorize(authz, domain.PermProductsWrite, withID("product", h.update)))
+	mux.HandleFunc("DELETE /products/{id}", authorize(authz, domain.PermProductsWrite, withID("product", h.delete)))
+	mux.HandleFunc("GET /categories/{id}/products", authorize(authz, domain.PermProductsRead, withID("category", h.getByCategory)))
 }
 
 // GetAllProducts godoc
@@ -63,7 +36,7 @@ func (h *ProductHandler) handleProductByID(w http.ResponseWriter, r *http.Reques
 //	@Router			/products [get]
 func (h *ProductHandler) getAll(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
-	products, err := h.service.GetAll(name)
+	products, err := h.service.GetAll(r.Context(), name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -84,7 +57,7 @@ func (h *ProductHandler) getAll(w http.ResponseWriter, r *http.Request) {
 //	@Failure		404	{string}	string	"Product not found"
 //	@Router			/products/{id} [get]
 func (h *ProductHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
-	product, err := h.service.GetByID(id)
+	product, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
@@ -93,6 +66,30 @@ func (h *ProductHandler) getByID(w http.ResponseWriter, r *http.Request, id int)
 	json.NewEncoder(w).Encode(product)
 }
 
+// GetCategoryProducts godoc
+//
+//	@Summary		List the products of a category
+//	@Description	Get the products in a category
+//	@Tags			products
+//	@Produce		json
+//	@Param			id	path		int	true	"Category ID"
+//	@Success		200	{array}		domain.Product
+//	@Failure		404	{string}	string	"category not found"
+//	@Router			/categories/{id}/products [get]
+func (h *ProductHandler) getByCategory(w http.ResponseWriter, r *http.Request, categoryID int) {
+	products, err := h.service.GetByCategory(r.Context(), categoryID)
+	if errors.Is(err, service.ErrCategoryNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
 // CreateProduct godoc
 //
 //	@Summary		Create a new product
@@ -102,6 +99,7 @@ func (h *ProductHandler) getByID(w http.ResponseWriter, r *http.Request, id int)
 //	@Produce		json
 //	@Param			product	body		domain.Product	true	"Product Data"
 //	@Success		201		{object}	domain.Product
+//	@Failure		400		{string}	string	"Invalid product"
 //	@Router			/products [post]
 func (h *ProductHandler) create(w http.ResponseWriter, r *http.Request) {
 	var product domain.Product
@@ -109,7 +107,11 @@ func (h *ProductHandler) create(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	createdProduct, err := h.service.Create(product)
+	createdProduct, err := h.service.Create(r.Context(), product)
+	if errors.Is(err, service.ErrInvalidProduct) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -125,7 +127,15 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request, id int)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	updatedProduct, err := h.service.Update(id, product)
+	updatedProduct, err := h.service.Update(r.Context(), id, product)
+	if errors.Is(err, service.ErrInvalidProduct) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, service.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -135,7 +145,7 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request, id int)
 }
 
 func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
-	if err := h.service.Delete(id); err != nil {
+	if err := h.service.Delete(r.Context(), id); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/fake"
+	"cateogry-api/internal/service"
+	"net/http"
+	"testing"
+)
+
+func newProductMux(repo *fake.ProductRepository) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewProductHandler(service.NewProductService(repo, nil)).RegisterRoutes(mux, nil)
+	return mux
+}
+
+func TestProductHandler(t *testing.T) {
+	mux := newProductMux(fake.NewProductRepository(
+		domain.Product{Name: "Wireless Mouse", Price: 150000, Stock: 5, CategoryID: 1},
+	))
+
+	rec := request(t, mux, http.MethodPost, "/products", `{"name":"Clean Code","price":450000,"stock":2,"category_id":2}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /products: status = %d, want 201", rec.Code)
+	}
+	var created domain.Product
+	decodeBody(t, rec, &created)
+	if created.ID != 2 || created.Price != 450000 {
+		t.Fatalf("POST /products = %+v", created)
+	}
+
+	var all []domain.Product
+	decodeBody(t, request(t, mux, http.MethodGet, "/products", ""), &all)
+	if len(all) != 2 {
+		t.Errorf("GET /products returned %d products, want 2", len(all))
+	}
+	var found []domain.Product
+	decodeBody(t, request(t, mux, http.MethodGet, "/products?name=CLEAN", ""), &found)
+	if len(found) != 1 || found[0].ID != 2 {
+		t.Errorf("GET /products?name=CLEAN = %+v, want product 2 only", found)
+	}
+
+	rec = request(t, mux, http.MethodPut, "/products/2", `{"name":"Clean Code","price":400000,"stock":2,"category_id":2}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PUT /products/2: status = %d, want 200", rec.Code)
+	}
+	var got domain.Product
+	decodeBody(t, request(t, mux, http.MethodGet, "/products/2", ""), &got)
+	if got.Price != 400000 {
+		t.Errorf("GET /products/2 after PUT: price = %d, want 400000", got.Price)
+	}
+
+	if rec := request(t, mux, http.MethodDelete, "/products/2", ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /products/2: status = %d, want 204", rec.Code)
+	}
+	if rec := request(t, mux, http.MethodGet, "/products/2", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET /products/2 after DELETE: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestProductHandlerCategoryProducts(t *testing.T) {
+	mux := newProductMux(fake.NewProductRepository(
+		domain.Product{Name: "Wireless Mouse", Price: 150000, CategoryID: 1},
+		domain.Product{Name: "Clean Code", Price: 450000, CategoryID: 2},
+		domain.Product{Name: "USB-C Charger", Price: 250000, CategoryID: 1},
+	))
+
+	var products []domain.Product
+	decodeBody(t, request(t, mux, http.MethodGet, "/categories/1/products", ""), &products)
+	if len(products) != 2 || products[0].Name != "Wireless Mouse" || products[1].Name != "USB-C Charger" {
+		t.Errorf("GET /categories/1/products = %+v, want the mouse and the charger", products)
+	}
+
+	decodeBody(t, request(t, mux, http.MethodGet, "/categories/3/products", ""), &products)
+	if products == nil || len(products) != 0 {
+		t.Errorf("GET /categories/3/products = %#v, want an empty list", products)
+	}
+
+	if rec := request(t, mux, http.MethodGet, "/categories/x/products", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET /categories/x/products: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestProductHandlerBadRequests(t *testing.T) {
+	checkStatuses(t, newProductMux(fake.NewProductRepository()), []statusTest{
+		{http.MethodPost, "/products", `[`, http.StatusBadRequest},
+		{http.MethodPut, "/products/1", `[`, http.StatusBadRequest},
+		{http.MethodPost, "/products", `{"name":"Keyboard","price":-1}`, http.StatusBadRequest},
+		{http.MethodPost, "/products", `{"name":"Keyboard","price":100000,"cost_price":-1}`, http.StatusBadRequest},
+		{http.MethodPut, "/products/1", `{"name":"Keyboard","price":100000,"cost_price":-1}`, http.StatusBadRequest},
+		{http.MethodPut, "/products/1", `{"name":"Keyboard","price":100000}`, http.StatusNotFound},
+		{http.MethodGet, "/products/x", "", http.StatusBadRequest},
+		{http.MethodDelete, "/products", "", http.StatusMethodNotAllowed},
+		{http.MethodPatch, "/products/1", "", http.StatusMethodNotAllowed},
+	})
+}
+
+func TestProductHandlerRepositoryError(t *testing.T) {
+	repo := fake.NewProductRepository()
+	repo.Err = fake.ErrFailed
+	checkStatuses(t, newProductMux(repo), []statusTest{
+		{http.MethodGet, "/products", "", http.StatusInternalServerError},
+		{http.MethodPost, "/products", `{"name":"Keyboard"}`, http.StatusInternalServerError},
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type PromotionHandler struct {
+	service *service.PromotionService
+}
+
+func NewPromotionHandler(service *service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+func (h *PromotionHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /promotions", authorize(authz, domain.PermPromotionsRead, h.getAll))
+	mux.HandleFunc("POST /promotions", authorize(authz, domain.PermPromotionsWrite, h.create))
+	mux.HandleFunc("GET /promotions/{id}", authorize(authz, domain.PermPromotionsRead, withID("promotion", h.getByID)))
+	mux.HandleFunc("PUT /promotions/{id}", authorize(authz, domain.PermPromotionsWrite, withID("promotion", h.update)))
+	mux.HandleFunc("DELETE /promotions/{id}", authorize(authz, domain.PermPromotionsWrite, withID("promotion", h.delete)))
+}
+
+// promotionErrorStatus maps validation errors to 400 and everything else to fallback.
+func promotionErrorStatus(err error, fallback int) int {
+	if errors.Is(err, service.ErrInvalidPromotion) {
+		return http.StatusBadRequest
+	}
+	return fallback
+}
+
+// GetAllPromotions godoc
+//
+//	@Summary		Get all promotions
+//	@Description	Get all promotions, including inactive and expired ones
+//	@Tags			promotions
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}	domain.Promotion
+//	@Router			/promotions [get]
+func (h *PromotionHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.service.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+// GetPromotionByID godoc
+//
+//	@Summary		Get a promotion by ID
+//	@Description	Get a promotion by ID
+//	@Tags			promotions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Promotion ID"
+//	@Success		200	{object}	domain.Promotion
+//	@Failure		404	{string}	string	"Promotion not found"
+//	@Router			/promotions/{id} [get]
+func (h *PromotionHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	promotion, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// CreatePromotion godoc
+//
+//	@Summary		Create a new promotion
+//	@Description	Create a percentage, fixed or buy_x_get_y promotion. Promotions without a code apply automatically.
+//	@Tags			promotions
+//	@Accept			json
+//	@Produce		json
+//	@Param			promotion	body		domain.Promotion	true	"Promotion Data"
+//	@Success		201			{object}	domain.Promotion
+//	@Failure		400			{string}	string	"Invalid promotion"
+//	@Router			/promotions [post]
+func (h *PromotionHandler) create(w http.ResponseWriter, r *http.Request) {
+	var promotion domain.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	createdPromotion, err := h.service.Create(r.Context(), promotion)
+	if err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(createdPromotion)
+}
+
+func (h *PromotionHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var promotion domain.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	updatedPromotion, err := h.service.Update(r.Context(), id, promotion)
+	if err != nil {
+		http.Error(w, err.Error(), promotionErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedPromotion)
+}
+
+func (h *PromotionHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
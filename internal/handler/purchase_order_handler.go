@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type PurchaseOrderHandler struct {
+	service *service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service *service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+func (h *PurchaseOrderHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /purchase-orders", authorize(authz, domain.PermPurchaseOrdersRead, h.getAll))
+	mux.HandleFunc("POST /purchase-orders", authorize(authz, domain.PermPurchaseOrdersWrite, h.create))
+	mux.HandleFunc("GET /purchase-orders/{id}", authorize(authz, domain.PermPurchaseOrdersRead, withID("purchase order", h.getByID)))
+	mux.HandleFunc("PUT /purchase-orders/{id}", authorize(authz, domain.PermPurchaseOrdersWrite, withID("purchase order", h.update)))
+	mux.HandleFunc("DELETE /purchase-orders/{id}", authorize(authz, domain.PermPurchaseOrdersWrite, withID("purchase order", h.delete)))
+	mux.HandleFunc("POST /purchase-orders/{id}/order", authorize(authz, domain.PermPurchaseOrdersWrite, withID("purchase order", h.order)))
+	mux.HandleFunc("POST /purchase-orders/{id}/receive", authorize(authz, domain.PermPurchaseOrdersWrite, withID("purchase order", h.receive)))
+}
+
+// purchaseOrderErrorStatus maps validation errors to 400, unknown orders,
+// locations and products to 404, status conflicts to 409, receipts that do
+// not match the order to 422 and everything else to 500.
+func purchaseOrderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidPurchaseOrder):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrPurchaseOrderNotFound), errors.Is(err, service.ErrLocationNotFound),
+		errors.Is(err, service.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrPurchaseOrderStatus):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrInvalidReceipt):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writePurchaseOrder(w http.ResponseWriter, status int, po *domain.PurchaseOrder) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(po)
+}
+
+// GetAllPurchaseOrders godoc
+//
+//	@Summary		Get all purchase orders
+//	@Description	Get purchase orders, newest first, optionally by status
+//	@Tags			purchase-orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			status	query		string	false	"Status"	Enums(draft, ordered, partially_received, received)
+//	@Success		200		{array}		domain.PurchaseOrder
+//	@Failure		400		{string}	string	"Invalid status"
+//	@Router			/purchase-orders [get]
+func (h *PurchaseOrderHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	status := domain.PurchaseOrderStatus(r.URL.Query().Get("status"))
+	orders, err := h.service.GetAll(r.Context(), status)
+	if err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// GetPurchaseOrderByID godoc
+//
+//	@Summary		Get a purchase order by ID
+//	@Description	Get a purchase order with its items
+//	@Tags			purchase-orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Purchase Order ID"
+//	@Success		200	{object}	domain.PurchaseOrder
+//	@Failure		404	{string}	string	"Purchase order not found"
+//	@Router			/purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	po, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	writePurchaseOrder(w, http.StatusOK, po)
+}
+
+// CreatePurchaseOrder godoc
+//
+//	@Summary		Create a purchase order
+//	@Description	Create a draft purchase order; location_id 0 means the default location
+//	@Tags			purchase-orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			purchase_order	body		domain.PurchaseOrder	true	"Purchase Order Data"
+//	@Success		201				{object}	domain.PurchaseOrder
+//	@Failure		400				{string}	string	"Invalid purchase order"
+//	@Router			/purchase-orders [post]
+func (h *PurchaseOrderHandler) create(w http.ResponseWriter, r *http.Request) {
+	var po domain.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(r.Context(), po)
+	if err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	writePurchaseOrder(w, http.StatusCreated, created)
+}
+
+// UpdatePurchaseOrder godoc
+//
+//	@Summary		Update a purchase order
+//	@Description	Replace the supplier, location, note and items of a draft purchase order
+//	@Tags			purchase-orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		int						true	"Purchase Order ID"
+//	@Param			purchase_order	body		domain.PurchaseOrder	true	"Purchase Order Data"
+//	@Success		200				{object}	domain.PurchaseOrder
+//	@Failure		400				{string}	string	"Invalid purchase order"
+//	@Failure		404				{string}	string	"Purchase order not found"
+//	@Failure		409				{string}	string	"Purchase order is not a draft"
+//	@Router			/purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var po domain.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(r.Context(), id, po)
+	if err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	writePurchaseOrder(w, http.StatusOK, updated)
+}
+
+// DeletePurchaseOrder godoc
+//
+//	@Summary		Delete a purchase order
+//	@Description	Delete a draft purchase order
+//	@Tags			purchase-orders
+//	@Param			id	path		int	true	"Purchase Order ID"
+//	@Success		204	{string}	string	"No Content"
+//	@Failure		404	{string}	string	"Purchase order not found"
+//	@Failure		409	{string}	string	"Purchase order is not a draft"
+//	@Router			/purchase-orders/{id} [delete]
+func (h *PurchaseOrderHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// OrderPurchaseOrder godoc
+//
+//	@Summary		Place a purchase order
+//	@Description	Move a draft purchase order to ordered
+//	@Tags			purchase-orders
+//	@Produce		json
+//	@Param			id	path		int	true	"Purchase Order ID"
+//	@Success		200	{object}	domain.PurchaseOrder
+//	@Failure		409	{string}	string	"Purchase order is not a draft"
+//	@Router			/purchase-orders/{id}/order [post]
+func (h *PurchaseOrderHandler) order(w http.ResponseWriter, r *http.Request, id int) {
+	po, err := h.service.Order(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	writePurchaseOrder(w, http.StatusOK, po)
+}
+
+// ReceivePurchaseOrder godoc
+//
+//	@Summary		Receive stock for a purchase order
+//	@Description	Add the arrived quantities to stock at the order's location and record their cost price
+//	@Tags			purchase-orders
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"Purchase Order ID"
+//	@Param			receipt	body		domain.ReceiveRequest	true	"Received items"
+//	@Success		200		{object}	domain.PurchaseOrder
+//	@Failure		400		{string}	string	"Invalid receipt"
+//	@Failure		404		{string}	string	"Purchase order not found"
+//	@Failure		409		{string}	string	"Purchase order cannot receive stock"
+//	@Failure		422		{string}	string	"Items not on the order or more than outstanding"
+//	@Router			/purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) receive(w http.ResponseWriter, r *http.Request, id int) {
+	var req domain.ReceiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	po, err := h.service.Receive(r.Context(), id, req)
+	if err != nil {
+		http.Error(w, err.Error(), purchaseOrderErrorStatus(err))
+		return
+	}
+	writePurchaseOrder(w, http.StatusOK, po)
+}
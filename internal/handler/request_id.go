@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"cateogry-api/internal/service"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestID gives every request an ID, taken from a well-formed incoming
+// X-Request-ID header or generated, echoes it in the response and stores it
+// in the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(service.WithRequestID(r.Context(), id)))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts up to 64 letters, digits, dashes and underscores,
+// so client supplied IDs are safe to log and store.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"cateogry-api/internal/logging"
+	"cateogry-api/internal/response"
+	"cateogry-api/internal/service"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogRequests gives each request a logger tagged with its request ID (and
+// trace ID when the request is traced), stored in the request context, and
+// logs one line per request once it completes. It must run inside RequestID.
+func LogRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		reqLogger := logger.With("request_id", service.RequestIDFromContext(r.Context()))
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			reqLogger = reqLogger.With("trace_id", sc.TraceID().String())
+		}
+		rec := response.NewRecorder(w)
+
+		next.ServeHTTP(rec, r.WithContext(logging.WithLogger(r.Context(), reqLogger)))
+
+		level := slog.LevelInfo
+		if rec.Status() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		reqLogger.Log(r.Context(), level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.Status(),
+			"bytes", rec.Bytes(),
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"remote_addr", r.RemoteAddr,
+		)
+	})
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestTimeout gives every request a deadline d from now. Work done under
+// the request context, including queries still running, is cancelled when it
+// passes or the client disconnects. A non-positive d adds no deadline.
+func RequestTimeout(d time.Duration, next http.Handler) http.Handler {
+	if d <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
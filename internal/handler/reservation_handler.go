@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type ReservationHandler struct {
+	service *service.ReservationService
+}
+
+func NewReservationHandler(service *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{service: service}
+}
+
+func (h *ReservationHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("POST /reservations", authorize(authz, domain.PermCheckoutCreate, h.create))
+	mux.HandleFunc("GET /reservations/{id}", authorize(authz, domain.PermCheckoutCreate, withID("reservation", h.getByID)))
+	mux.HandleFunc("DELETE /reservations/{id}", authorize(authz, domain.PermCheckoutCreate, withID("reservation", h.release)))
+}
+
+// CreateReservation godoc
+//
+//	@Summary		Reserve stock
+//	@Description	Hold product quantities for a limited time. Held stock is not available to other orders until the reservation is consumed by checkout, released or expires.
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json
+//	@Param			reservation	body		domain.ReservationRequest	true	"Items and optional TTL"
+//	@Success		201			{object}	domain.StockReservation
+//	@Failure		400			{string}	string	"Invalid reservation"
+//	@Failure		404			{string}	string	"Product not found"
+//	@Failure		409			{string}	string	"Not enough stock"
+//	@Router			/reservations [post]
+func (h *ReservationHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req domain.ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := h.service.Create(r.Context(), req)
+	if errors.Is(err, service.ErrInvalidReservation) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeError(w, err, checkoutErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// GetReservation godoc
+//
+//	@Summary		Get a reservation
+//	@Description	Get a reservation and its held items
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Reservation ID"
+//	@Success		200	{object}	domain.StockReservation
+//	@Failure		404	{string}	string	"Reservation not found"
+//	@Router			/reservations/{id} [get]
+func (h *ReservationHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	reservation, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// ReleaseReservation godoc
+//
+//	@Summary		Release a reservation
+//	@Description	Give the held stock back before the reservation expires
+//	@Tags			reservations
+//	@Param			id	path	int	true	"Reservation ID"
+//	@Success		204
+//	@Failure		404	{string}	string	"Reservation not found"
+//	@Router			/reservations/{id} [delete]
+func (h *ReservationHandler) release(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Release(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrReservationNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"cateogry-api/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// errorCodeHeader names, for the errors clients act on, what went wrong in a
+// form that does not change with the wording of the message.
+const errorCodeHeader = "X-Error-Code"
+
+// errorCodeInsufficientStock is sent when a product does not have enough
+// stock.
+const errorCodeInsufficientStock = "insufficient_stock"
+
+// withID passes the {id} wildcard of the route to next as an int. A value
+// that is not a number is a 400 naming what, e.g. "Invalid category ID".
+func withID(what string, next func(http.ResponseWriter, *http.Request, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "Invalid "+what+" ID", http.StatusBadRequest)
+			return
+		}
+		next(w, r, id)
+	}
+}
+
+// writeError answers err with status and, when clients can act on err, its
+// errorCodeHeader.
+func writeError(w http.ResponseWriter, err error, status int) {
+	if errors.Is(err, service.ErrInsufficientStock) {
+		w.Header().Set(errorCodeHeader, errorCodeInsufficientStock)
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"net/http"
+)
+
+type SupplierHandler struct {
+	service *service.SupplierService
+}
+
+func NewSupplierHandler(service *service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: service}
+}
+
+func (h *SupplierHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /suppliers", authorize(authz, domain.PermSuppliersRead, h.getAll))
+	mux.HandleFunc("POST /suppliers", authorize(authz, domain.PermSuppliersWrite, h.create))
+	mux.HandleFunc("GET /suppliers/{id}", authorize(authz, domain.PermSuppliersRead, withID("supplier", h.getByID)))
+	mux.HandleFunc("PUT /suppliers/{id}", authorize(authz, domain.PermSuppliersWrite, withID("supplier", h.update)))
+	mux.HandleFunc("DELETE /suppliers/{id}", authorize(authz, domain.PermSuppliersWrite, withID("supplier", h.delete)))
+}
+
+// GetAllSuppliers godoc
+//
+//	@Summary		Get all suppliers
+//	@Description	Get all suppliers
+//	@Tags			suppliers
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}	domain.Supplier
+//	@Router			/suppliers [get]
+func (h *SupplierHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.service.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suppliers)
+}
+
+// GetSupplierByID godoc
+//
+//	@Summary		Get a supplier by ID
+//	@Description	Get a supplier by ID
+//	@Tags			suppliers
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Supplier ID"
+//	@Success		200	{object}	domain.Supplier
+//	@Failure		404	{string}	string	"Supplier not found"
+//	@Router			/suppliers/{id} [get]
+func (h *SupplierHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	supplier, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(supplier)
+}
+
+// CreateSupplier godoc
+//
+//	@Summary		Create a new supplier
+//	@Description	Create a new supplier
+//	@Tags			suppliers
+//	@Accept			json
+//	@Produce		json
+//	@Param			supplier	body		domain.Supplier	true	"Supplier Data"
+//	@Success		201			{object}	domain.Supplier
+//	@Router			/suppliers [post]
+func (h *SupplierHandler) create(w http.ResponseWriter, r *http.Request) {
+	var supplier domain.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	createdSupplier, err := h.service.Create(r.Context(), supplier)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(createdSupplier)
+}
+
+func (h *SupplierHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var supplier domain.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	updatedSupplier, err := h.service.Update(r.Context(), id, supplier)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedSupplier)
+}
+
+func (h *SupplierHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+type TaxHandler struct {
+	service *service.TaxService
+}
+
+func NewTaxHandler(service *service.TaxService) *TaxHandler {
+	return &TaxHandler{service: service}
+}
+
+func (h *TaxHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("GET /tax-rates", authorize(authz, domain.PermTaxesRead, h.getAll))
+	mux.HandleFunc("POST /tax-rates", authorize(authz, domain.PermTaxesWrite, h.create))
+	mux.HandleFunc("GET /tax-rates/{id}", authorize(authz, domain.PermTaxesRead, withID("tax rate", h.getByID)))
+	mux.HandleFunc("PUT /tax-rates/{id}", authorize(authz, domain.PermTaxesWrite, withID("tax rate", h.update)))
+	mux.HandleFunc("DELETE /tax-rates/{id}", authorize(authz, domain.PermTaxesWrite, withID("tax rate", h.delete)))
+}
+
+// taxErrorStatus maps validation errors to 400, unknown tax rates to 404 and
+// everything else to 500.
+func taxErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidTaxRate):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrTaxRateNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetAllTaxRates godoc
+//
+//	@Summary		Get all tax rates
+//	@Description	Get the default tax rate and every per-category override
+//	@Tags			taxes
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{array}	domain.TaxRate
+//	@Router			/tax-rates [get]
+func (h *TaxHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	taxRates, err := h.service.GetAll(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(taxRates)
+}
+
+// GetTaxRateByID godoc
+//
+//	@Summary		Get a tax rate by ID
+//	@Description	Get a tax rate by ID
+//	@Tags			taxes
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Tax Rate ID"
+//	@Success		200	{object}	domain.TaxRate
+//	@Failure		404	{string}	string	"Tax rate not found"
+//	@Router			/tax-rates/{id} [get]
+func (h *TaxHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	taxRate, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), taxErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(taxRate)
+}
+
+// CreateTaxRate godoc
+//
+//	@Summary		Create a new tax rate
+//	@Description	Create a tax rate in basis points. Omit category_id to set the default rate.
+//	@Tags			taxes
+//	@Accept			json
+//	@Produce		json
+//	@Param			taxRate	body		domain.TaxRate	true	"Tax Rate Data"
+//	@Success		201		{object}	domain.TaxRate
+//	@Failure		400		{string}	string	"Invalid tax rate"
+//	@Router			/tax-rates [post]
+func (h *TaxHandler) create(w http.ResponseWriter, r *http.Request) {
+	var taxRate domain.TaxRate
+	if err := json.NewDecoder(r.Body).Decode(&taxRate); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	createdTaxRate, err := h.service.Create(r.Context(), taxRate)
+	if err != nil {
+		http.Error(w, err.Error(), taxErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(createdTaxRate)
+}
+
+func (h *TaxHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var taxRate domain.TaxRate
+	if err := json.NewDecoder(r.Body).Decode(&taxRate); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	updatedTaxRate, err := h.service.Update(r.Context(), id, taxRate)
+	if err != nil {
+		http.Error(w, err.Error(), taxErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedTaxRate)
+}
+
+func (h *TaxHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), taxErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
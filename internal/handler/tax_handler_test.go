@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/fake"
+	"cateogry-api/internal/service"
+	"net/http"
+	"testing"
+)
+
+func newTaxMux(repo *fake.TaxRateRepository) *http.ServeMux {
+	mux := http.NewServeMux()
+	NewTaxHandler(service.NewTaxService(repo)).RegisterRoutes(mux, nil)
+	return mux
+}
+
+func TestTaxHandlerStatuses(t *testing.T) {
+	checkStatuses(t, newTaxMux(fake.NewTaxRateRepository(domain.TaxRate{Name: "VAT", Rate: 1100})), []statusTest{
+		{http.MethodGet, "/tax-rates/1", "", http.StatusOK},
+		{http.MethodGet, "/tax-rates/99", "", http.StatusNotFound},
+		{http.MethodPut, "/tax-rates/99", `{"name":"VAT","rate":1200}`, http.StatusNotFound},
+		{http.MethodDelete, "/tax-rates/99", "", http.StatusNotFound},
+		{http.MethodPut, "/tax-rates/1", `{"name":"VAT","rate":-1}`, http.StatusBadRequest},
+		{http.MethodDelete, "/tax-rates/1", "", http.StatusNoContent},
+	})
+}
+
+func TestTaxHandlerRepositoryError(t *testing.T) {
+	repo := fake.NewTaxRateRepository(domain.TaxRate{Name: "VAT", Rate: 1100})
+	repo.Err = fake.ErrFailed
+	checkStatuses(t, newTaxMux(repo), []statusTest{
+		{http.MethodGet, "/tax-rates/1", "", http.StatusInternalServerError},
+		{http.MethodPut, "/tax-rates/1", `{"name":"VAT","rate":1200}`, http.StatusInternalServerError},
+		{http.MethodDelete, "/tax-rates/1", "", http.StatusInternalServerError},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"cateogry-api/internal/domain"
 	"cateogry-api/internal/service"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 )
@@ -16,57 +17,91 @@ func NewTransactionHandler(service *service.TransactionService) *TransactionHand
 	return &TransactionHandler{service: service}
 }
 
-func (h *TransactionHandler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/checkout", h.handleCheckout)
-	mux.HandleFunc("/report/hari-ini", h.handleDailyReport)
-	mux.HandleFunc("/report", h.handleReport)
+func (h *TransactionHandler) RegisterRoutes(mux *http.ServeMux, authz *service.Authorizer) {
+	mux.HandleFunc("POST /checkout", authorize(authz, domain.PermCheckoutCreate, h.checkout))
+	mux.HandleFunc("GET /transactions/{id}", authorize(authz, domain.PermTransactionsRead, withID("transaction", h.getByID)))
+	mux.HandleFunc("GET /report/hari-ini", authorize(authz, domain.PermReportsRead, h.getDailyReport))
+	mux.HandleFunc("GET /report", authorize(authz, domain.PermReportsRead, h.getReport))
 }
 
-func (h *TransactionHandler) handleCheckout(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// checkoutErrorStatus maps validation errors to 400, unknown products,
+// locations and reservations to 404, stock, reservation and idempotency key
+// conflicts to 409 and everything else to 500.
+func checkoutErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidCheckout), errors.Is(err, service.ErrInvalidPayment),
+		errors.Is(err, service.ErrInvalidPromoCode):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrLocationNotFound),
+		errors.Is(err, service.ErrReservationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInsufficientStock), errors.Is(err, service.ErrReservationNotActive),
+		errors.Is(err, service.ErrNotReserved), errors.Is(err, service.ErrIdempotencyKeyUsed):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
 	}
+}
 
+func (h *TransactionHandler) checkout(w http.ResponseWriter, r *http.Request) {
 	var req domain.CheckoutRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-	println("TransactionHandler.handleCheckout", req.Items)
-	transaction, err := h.service.Checkout(req.Items)
+	req.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	if len(req.IdempotencyKey) > 255 {
+		http.Error(w, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
+		return
+	}
+	transaction, err := h.service.Checkout(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err, checkoutErrorStatus(err))
 		return
 	}
-	println("TransactionHandler.handleCheckout", transaction)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(transaction)
 }
 
-func (h *TransactionHandler) handleDailyReport(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// GetTransactionByID godoc
+//
+//	@Summary		Get a transaction by ID
+//	@Description	Get a transaction with the product snapshot recorded at checkout
+//	@Tags			transactions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Transaction ID"
+//	@Success		200	{object}	domain.Transaction
+//	@Failure		404	{string}	string	"Transaction not found"
+//	@Router			/transactions/{id} [get]
+func (h *TransactionHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	transaction, err := h.service.GetByID(r.Context(), id)
+	if errors.Is(err, service.ErrTransactionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
-
-	// Assuming today's date
-	report, err := h.service.GetDailyReport(time.Now())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
+	json.NewEncoder(w).Encode(transaction)
 }
 
-func (h *TransactionHandler) handleReport(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+func (h *TransactionHandler) getDailyReport(w http.ResponseWriter, r *http.Request) {
+	// Assuming today's date
+	report, err := h.service.GetDailyReport(r.Context(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *TransactionHandler) getReport(w http.ResponseWriter, r *http.Request) {
 	startDateStr := r.URL.Query().Get("start_date")
 	endDateStr := r.URL.Query().Get("end_date")
 
@@ -93,7 +128,7 @@ func (h *TransactionHandler) handleReport(w http.ResponseWriter, r *http.Request
 	// Instruction example: start_date=2026-01-01&end_date=2026-02-01. Usually implies up to 2026-02-01.
 	// If we assume exclusive upper bound: [start, end)
 
-	report, err := h.service.GetReport(startDate, endDate)
+	report, err := h.service.GetReport(r.Context(), startDate, endDate)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
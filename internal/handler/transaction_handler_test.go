@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/fake"
+	"cateogry-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTransactionMux() (*http.ServeMux, *fake.TransactionRepository) {
+	products := fake.NewProductRepository(
+		domain.Product{Name: "Wireless Mouse", Price: 150000, Stock: 10, CategoryID: 1},
+	)
+	transactions := fake.NewTransactionRepository(products)
+	svc := service.NewTransactionService(transactions, fake.NewPromotionRepository(),
+		fake.NewTaxRateRepository(domain.TaxRate{Name: "PPN", Rate: 1100}), service.TaxSettings{}, nil)
+
+	mux := http.NewServeMux()
+	NewTransactionHandler(svc).RegisterRoutes(mux, nil)
+	return mux, transactions
+}
+
+func TestTransactionHandlerCheckout(t *testing.T) {
+	mux, _ := newTransactionMux()
+
+	rec := request(t, mux, http.MethodPost, "/checkout",
+		`{"items":[{"product_id":1,"quantity":2}],"payments":[{"method":"cash","amount":400000}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /checkout: status = %d, want 200 (%s)", rec.Code, rec.Body)
+	}
+	var tx domain.Transaction
+	decodeBody(t, rec, &tx)
+	if tx.TotalAmount != 333000 || len(tx.Payments) != 1 || tx.Payments[0].Change != 67000 {
+		t.Errorf("POST /checkout = total %d, payments %+v; want 333000 with 67000 change", tx.TotalAmount, tx.Payments)
+	}
+
+	var got domain.Transaction
+	decodeBody(t, request(t, mux, http.MethodGet, "/transactions/"+strconv.Itoa(tx.ID), ""), &got)
+	if got.ID != tx.ID || got.TotalAmount != tx.TotalAmount {
+		t.Errorf("GET /transactions/%d = #%d %d, want #%d %d", tx.ID, got.ID, got.TotalAmount, tx.ID, tx.TotalAmount)
+	}
+}
+
+func TestTransactionHandlerIdempotencyKey(t *testing.T) {
+	mux, _ := newTransactionMux()
+	body := `{"items":[{"product_id":1,"quantity":1}]}`
+
+	var first, again domain.Transaction
+	decodeBody(t, request(t, mux, http.MethodPost, "/checkout", body, "Idempotency-Key", "till-1-0042"), &first)
+	decodeBody(t, request(t, mux, http.MethodPost, "/checkout", body, "Idempotency-Key", "till-1-0042"), &again)
+	if again.ID != first.ID {
+		t.Errorf("retried checkout created transaction %d, want %d", again.ID, first.ID)
+	}
+
+	rec := request(t, mux, http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":2}]}`, "Idempotency-Key", "till-1-0042")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("POST /checkout reusing a key for another checkout: status = %d, want 409", rec.Code)
+	}
+
+	rec = request(t, mux, http.MethodPost, "/checkout", body, "Idempotency-Key", strings.Repeat("k", 256))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /checkout with a 256-character key: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestTransactionHandlerBadRequests(t *testing.T) {
+	mux, _ := newTransactionMux()
+	checkStatuses(t, mux, []statusTest{
+		{http.MethodGet, "/checkout", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/checkout", `{"items":`, http.StatusBadRequest},
+		{http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":1}],"payments":[{"method":"cash","amount":1000}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/checkout", `{"items":[]}`, http.StatusBadRequest},
+		{http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":0}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":1},{"product_id":1,"quantity":1}]}`, http.StatusBadRequest},
+		{http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":1}],"promo_codes":["NOPE"]}`, http.StatusBadRequest},
+		{http.MethodPost, "/checkout", `{"items":[{"product_id":99,"quantity":1}]}`, http.StatusNotFound},
+		{http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":11}]}`, http.StatusConflict},
+		{http.MethodGet, "/transactions/abc", "", http.StatusBadRequest},
+		{http.MethodGet, "/transactions/99", "", http.StatusNotFound},
+		{http.MethodDelete, "/transactions/1", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/report/hari-ini", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/report", "", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/report", "", http.StatusBadRequest},
+		{http.MethodGet, "/report?start_date=2026-01-01", "", http.StatusBadRequest},
+		{http.MethodGet, "/report?start_date=01-01-2026&end_date=2026-01-02", "", http.StatusBadRequest},
+		{http.MethodGet, "/report?start_date=2026-01-01&end_date=tomorrow", "", http.StatusBadRequest},
+	})
+}
+
+func TestTransactionHandlerErrorCode(t *testing.T) {
+	mux, _ := newTransactionMux()
+	rec := request(t, mux, http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":11}]}`)
+	if code := rec.Header().Get(errorCodeHeader); code != errorCodeInsufficientStock {
+		t.Errorf("checkout over stock: %s = %q, want %q", errorCodeHeader, code, errorCodeInsufficientStock)
+	}
+	rec = request(t, mux, http.MethodPost, "/checkout", `{"items":[{"product_id":99,"quantity":1}]}`)
+	if code := rec.Header().Get(errorCodeHeader); code != "" {
+		t.Errorf("checkout of an unknown product: %s = %q, want none", errorCodeHeader, code)
+	}
+}
+
+func TestTransactionHandlerReports(t *testing.T) {
+	mux, transactions := newTransactionMux()
+	if rec := request(t, mux, http.MethodPost, "/checkout", `{"items":[{"product_id":1,"quantity":1}]}`); rec.Code != http.StatusOK {
+		t.Fatalf("POST /checkout: status = %d, want 200", rec.Code)
+	}
+
+	var today domain.DailyReport
+	decodeBody(t, request(t, mux, http.MethodGet, "/report/hari-ini", ""), &today)
+	if today.TotalTransactions != 1 || today.TotalRevenue != 166500 {
+		t.Errorf("GET /report/hari-ini = %d transactions, %d revenue; want 1, 166500", today.TotalTransactions, today.TotalRevenue)
+	}
+
+	now := time.Now().UTC()
+	path := "/report?start_date=" + now.Format("2006-01-02") + "&end_date=" + now.AddDate(0, 0, 1).Format("2006-01-02")
+	var ranged domain.DailyReport
+	decodeBody(t, request(t, mux, http.MethodGet, path, ""), &ranged)
+	if ranged.TotalTransactions != 1 {
+		t.Errorf("GET %s = %d transactions, want 1", path, ranged.TotalTransactions)
+	}
+
+	transactions.Err = fake.ErrFailed
+	checkStatuses(t, mux, []statusTest{
+		{http.MethodGet, "/report/hari-ini", "", http.StatusInternalServerError},
+		{http.MethodGet, path, "", http.StatusInternalServerError},
+		{http.MethodGet, "/transactions/1", "", http.StatusInternalServerError},
+	})
+}
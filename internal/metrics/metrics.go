@@ -0,0 +1,167 @@
+// Package metrics defines the Prometheus metrics the API exposes on /metrics.
+package metrics
+
+import (
+	"cateogry-api/internal/response"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "api"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	checkouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "checkouts_total",
+		Help:      "Completed checkouts.",
+	})
+
+	checkoutAmount = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "checkout_amount_total",
+		Help:      "Sum of the total amount of completed checkouts.",
+	})
+
+	stockOuts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stock_outs_total",
+		Help:      `Stock-out events: "depleted" when a sale leaves no sellable stock, "rejected" when a sale asks for more than there is.`,
+	}, []string{"kind"})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "background_job_runs_total",
+		Help:      "Background job runs by job and result (success or error).",
+	}, []string{"job", "result"})
+
+	jobLastRun = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "background_job_last_run_timestamp_seconds",
+		Help:      "Unix time a background job last finished, by job and result.",
+	}, []string{"job", "result"})
+
+	reservationsExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reservations_expired_total",
+		Help:      "Stock reservations expired by the background expirer.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		checkouts, checkoutAmount, stockOuts,
+		jobRuns, jobLastRun, reservationsExpired,
+	)
+}
+
+// Handler serves every registered metric in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exposes the connection pool statistics of db as go_sql_* metrics
+// labelled with dbName.
+func RegisterDB(db *sql.DB, dbName string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+func CheckoutCompleted(totalAmount int) {
+	checkouts.Inc()
+	checkoutAmount.Add(float64(totalAmount))
+}
+
+// StockDepleted records n products whose sellable stock a sale used up.
+func StockDepleted(n int) {
+	stockOuts.WithLabelValues("depleted").Add(float64(n))
+}
+
+func StockRejected() {
+	stockOuts.WithLabelValues("rejected").Inc()
+}
+
+// JobFinished records the outcome of one background job run.
+func JobFinished(job string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	jobRuns.WithLabelValues(job, result).Inc()
+	jobLastRun.WithLabelValues(job, result).SetToCurrentTime()
+}
+
+func ReservationsExpired(n int64) {
+	reservationsExpired.Add(float64(n))
+}
+
+// Instrument counts and times every request. route maps a request to the
+// pattern that serves it, so that IDs in paths do not become labels.
+func Instrument(route func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := response.NewRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		labels := prometheus.Labels{"method": r.Method, "route": route(r), "status": strconv.Itoa(rec.Status())}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// MuxRoute returns a route function for Instrument. Requests under prefix are
+// resolved against inner with the prefix stripped, as http.StripPrefix would;
+// everything else against outer. The route is the path of the matching
+// pattern, without its method. Requests no pattern matches, including those
+// answered with 405, share the route "unmatched".
+func MuxRoute(outer *http.ServeMux, prefix string, inner *http.ServeMux) func(*http.Request) string {
+	return func(r *http.Request) string {
+		if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok && strings.HasPrefix(rest, "/") {
+			if _, pattern := outer.Handler(r); pattern == prefix+"/" {
+				r2 := r.Clone(r.Context())
+				r2.URL.Path = rest
+				r2.URL.RawPath = ""
+				if _, pattern := inner.Handler(r2); pattern != "" {
+					return prefix + patternPath(pattern)
+				}
+				return "unmatched"
+			}
+		}
+		if _, pattern := outer.Handler(r); pattern != "" {
+			return patternPath(pattern)
+		}
+		return "unmatched"
+	}
+}
+
+// patternPath drops the method from a ServeMux pattern such as
+// "GET /categories/{id}".
+func patternPath(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
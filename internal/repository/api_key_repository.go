@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type PostgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAPIKeyRepository(db *sql.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{db: db}
+}
+
+func (r *PostgresAPIKeyRepository) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, role, prefix, created_at, last_used_at, revoked_at FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]domain.APIKey, 0)
+	for rows.Next() {
+		var k domain.APIKey
+		if err := rows.Scan(&k.ID, &k.Name, &k.Role, &k.Prefix, &k.CreatedAt, &k.LastUsedAt, &k.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// Create stores a key by its hash. The plaintext key never reaches the database.
+func (r *PostgresAPIKeyRepository) Create(ctx context.Context, k domain.APIKey, keyHash string) (domain.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (name, role, prefix, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query, k.Name, string(k.Role), k.Prefix, keyHash, time.Now()).Scan(&k.ID, &k.CreatedAt)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	return k, nil
+}
+
+// Revoke disables a key. Revoking an already revoked key reports it as not found.
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL", time.Now(), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Touch looks up an unrevoked key by hash and records that it was just used.
+func (r *PostgresAPIKeyRepository) Touch(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	query := `
+		UPDATE api_keys SET last_used_at = $1
+		WHERE key_hash = $2 AND revoked_at IS NULL
+		RETURNING id, name, role, prefix, created_at, last_used_at
+	`
+	var k domain.APIKey
+	err := r.db.QueryRowContext(ctx, query, time.Now(), keyHash).Scan(&k.ID, &k.Name, &k.Role, &k.Prefix, &k.CreatedAt, &k.LastUsedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// RunInTx runs fn in one database transaction. Repository calls made with
+// the ctx passed to fn, including Create, take part in it, and it commits
+// only if fn returns nil.
+func (r *PostgresAuditRepository) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTx(ctx, r.db, fn)
+}
+
+func (r *PostgresAuditRepository) Create(ctx context.Context, e domain.AuditEntry) error {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO audit_log (actor, action, entity_type, entity_id, before, after, changes, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, e.Actor, string(e.Action), e.EntityType, e.EntityID,
+		nullJSON(e.Before), nullJSON(e.After), string(changes), e.RequestID, time.Now())
+	return err
+}
+
+// Find returns matching entries, newest first.
+func (r *PostgresAuditRepository) Find(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.Actor != "" {
+		add("actor = $%d", f.Actor)
+	}
+	if f.Action != "" {
+		add("action = $%d", string(f.Action))
+	}
+	if f.EntityType != "" {
+		add("entity_type = $%d", f.EntityType)
+	}
+	if f.EntityID != 0 {
+		add("entity_id = $%d", f.EntityID)
+	}
+	if !f.From.IsZero() {
+		add("created_at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("created_at < $%d", f.To)
+	}
+
+	query := `
+		SELECT id, actor, action, entity_type, entity_id, COALESCE(before::text, ''), COALESCE(after::text, ''),
+		       changes::text, request_id, created_at
+		FROM audit_log
+	`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, f.Limit)
+	query += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var e domain.AuditEntry
+		var before, after, changes string
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &before, &after, &changes, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if before != "" {
+			e.Before = json.RawMessage(before)
+		}
+		if after != "" {
+			e.After = json.RawMessage(after)
+		}
+		if err := json.Unmarshal([]byte(changes), &e.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func nullJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
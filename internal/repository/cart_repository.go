@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrCartNotFound is returned when a cart does not exist, has expired or
+	// is no longer open for the requested change.
+	ErrCartNotFound = errors.New("cart not found")
+	// ErrCartEmpty is returned when a cart without items is checked out.
+	ErrCartEmpty = errors.New("cart is empty")
+)
+
+type PostgresCartRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCartRepository(db *sql.DB) *PostgresCartRepository {
+	return &PostgresCartRepository{db: db}
+}
+
+func (r *PostgresCartRepository) Create(ctx context.Context, expiresAt time.Time) (*domain.Cart, error) {
+	query := `
+		INSERT INTO carts (status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		RETURNING id, status, expires_at, created_at, updated_at
+	`
+	c := domain.Cart{Items: make([]domain.CartItem, 0)}
+	err := r.db.QueryRowContext(ctx, query, string(domain.CartOpen), expiresAt, time.Now()).Scan(&c.ID, &c.Status, &c.ExpiresAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetByID returns an unexpired cart with its items priced from the current
+// products table.
+func (r *PostgresCartRepository) GetByID(ctx context.Context, id int) (*domain.Cart, error) {
+	query := `
+		SELECT id, status, transaction_id, expires_at, created_at, updated_at
+		FROM carts
+		WHERE id = $1 AND (expires_at > $2 OR status <> $3)
+	`
+	var c domain.Cart
+	err := r.db.QueryRowContext(ctx, query, id, time.Now(), string(domain.CartOpen)).Scan(&c.ID, &c.Status, &c.TransactionID, &c.ExpiresAt, &c.CreatedAt, &c.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ci.product_id, p.name, p.price, ci.quantity, p.stock - `+reservedStockSQL+`
+		FROM cart_items ci
+		JOIN products p ON ci.product_id = p.id
+		WHERE ci.cart_id = $1 AND p.deleted_at IS NULL
+		ORDER BY p.name
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	c.Items = make([]domain.CartItem, 0)
+	for rows.Next() {
+		var item domain.CartItem
+		if err := rows.Scan(&item.ProductID, &item.ProductName, &item.UnitPrice, &item.Quantity, &item.Stock); err != nil {
+			return nil, err
+		}
+		item.Subtotal = item.UnitPrice * item.Quantity
+		item.InStock = item.Stock >= item.Quantity
+		c.Subtotal += item.Subtotal
+		c.Items = append(c.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// SetItem sets the quantity of a product in an open cart, adding the line if
+// needed, and pushes the cart's expiry out to expiresAt.
+func (r *PostgresCartRepository) SetItem(ctx context.Context, cartID, productID, quantity int, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := touchOpenCart(ctx, tx, cartID, expiresAt); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cart_items (cart_id, product_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity
+	`, cartID, productID, quantity)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddItem increments the quantity of a product in an open cart.
+func (r *PostgresCartRepository) AddItem(ctx context.Context, cartID, productID, quantity int, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := touchOpenCart(ctx, tx, cartID, expiresAt); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cart_items (cart_id, product_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (cart_id, product_id) DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity
+	`, cartID, productID, quantity)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresCartRepository) RemoveItem(ctx context.Context, cartID, productID int, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := touchOpenCart(ctx, tx, cartID, expiresAt); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id = $1 AND product_id = $2", cartID, productID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("cart item not found")
+	}
+	return tx.Commit()
+}
+
+// lockedCartItems returns the lines of a cart the caller has locked, so no
+// line can change until tx ends.
+func lockedCartItems(ctx context.Context, tx *sql.Tx, cartID int) ([]domain.CheckoutItem, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT product_id, quantity FROM cart_items WHERE cart_id = $1", cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.CheckoutItem
+	for rows.Next() {
+		var item domain.CheckoutItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrCartEmpty
+	}
+	return items, nil
+}
+
+func touchOpenCart(ctx context.Context, tx *sql.Tx, cartID int, expiresAt time.Time) error {
+	now := time.Now()
+	result, err := tx.ExecContext(ctx,
+		"UPDATE carts SET expires_at = $1, updated_at = $2 WHERE id = $3 AND status = $4 AND expires_at > $2",
+		expiresAt, now, cartID, string(domain.CartOpen))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrCartNotFound
+	}
+	return nil
+}
+
+func (r *PostgresCartRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM carts WHERE id = $1 AND status = $2", id, string(domain.CartOpen))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrCartNotFound
+	}
+	return nil
+}
+
+// CleanUpExpired deletes open carts whose expiry has passed.
+func (r *PostgresCartRepository) CleanUpExpired(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM carts WHERE status = $1 AND expires_at <= $2", string(domain.CartOpen), time.Now())
+	return err
+}
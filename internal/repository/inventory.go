@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrLocationNotFound = errors.New("location not found")
+	// ErrInsufficientStock is wrapped when a sale, reservation or transfer
+	// asks for more than a product has available.
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// resolveLocation returns locationID if it names a live location, or the
+// default location's ID when locationID is 0.
+func resolveLocation(ctx context.Context, q queryRower, locationID int) (int, error) {
+	var id int
+	var err error
+	if locationID == 0 {
+		err = q.QueryRowContext(ctx, "SELECT id FROM locations WHERE is_default AND deleted_at IS NULL").Scan(&id)
+	} else {
+		err = q.QueryRowContext(ctx, "SELECT id FROM locations WHERE id = $1 AND deleted_at IS NULL", locationID).Scan(&id)
+	}
+	if err == sql.ErrNoRows {
+		return 0, ErrLocationNotFound
+	}
+	return id, err
+}
+
+// adjustStock changes a product's stock at one location by delta and keeps
+// products.stock, the total over all locations, in step. It is the only place
+// stock levels change, whether by sale, transfer or receiving. The location's
+// quantity may not go below zero.
+func adjustStock(ctx context.Context, tx *sql.Tx, productID, locationID, delta int) error {
+	var quantity int
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO product_stocks (product_id, location_id, quantity) VALUES ($1, $2, $3)
+		ON CONFLICT (product_id, location_id) DO UPDATE SET quantity = product_stocks.quantity + EXCLUDED.quantity
+		RETURNING quantity
+	`, productID, locationID, delta).Scan(&quantity)
+	if err != nil {
+		return err
+	}
+	if quantity < 0 {
+		return fmt.Errorf("%w: stock for product id %d at location %d is not enough. available: %d, requested: %d",
+			ErrInsufficientStock, productID, locationID, quantity-delta, -delta)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE products SET stock = stock + $1 WHERE id = $2", delta, productID)
+	return err
+}
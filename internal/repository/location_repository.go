@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type PostgresLocationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLocationRepository(db *sql.DB) *PostgresLocationRepository {
+	return &PostgresLocationRepository{db: db}
+}
+
+func (r *PostgresLocationRepository) GetAll(ctx context.Context) ([]domain.Location, error) {
+	query := "SELECT id, name, type, address, is_default, created_at, updated_at FROM locations WHERE deleted_at IS NULL ORDER BY id"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := make([]domain.Location, 0)
+	for rows.Next() {
+		var l domain.Location
+		if err := rows.Scan(&l.ID, &l.Name, &l.Type, &l.Address, &l.IsDefault, &l.CreatedAt, &l.UpdatedAt); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+	return locations, rows.Err()
+}
+
+func (r *PostgresLocationRepository) GetByID(ctx context.Context, id int) (*domain.Location, error) {
+	query := "SELECT id, name, type, address, is_default, created_at, updated_at FROM locations WHERE id = $1 AND deleted_at IS NULL"
+	var l domain.Location
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&l.ID, &l.Name, &l.Type, &l.Address, &l.IsDefault, &l.CreatedAt, &l.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// Create inserts a location. Making it the default takes the flag away from
+// the previous default location.
+func (r *PostgresLocationRepository) Create(ctx context.Context, l domain.Location) (domain.Location, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Location{}, err
+	}
+	defer tx.Rollback()
+
+	if l.IsDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE locations SET is_default = FALSE WHERE is_default"); err != nil {
+			return domain.Location{}, err
+		}
+	}
+	query := `
+		INSERT INTO locations (name, type, address, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, l.Name, string(l.Type), l.Address, l.IsDefault, time.Now()).Scan(&l.ID, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		return domain.Location{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.Location{}, err
+	}
+	return l, nil
+}
+
+// Update changes a location. The default flag can be moved to this location
+// but not cleared; make another location the default instead.
+func (r *PostgresLocationRepository) Update(ctx context.Context, id int, l domain.Location) (*domain.Location, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if l.IsDefault {
+		if _, err := tx.ExecContext(ctx, "UPDATE locations SET is_default = FALSE WHERE is_default AND id <> $1", id); err != nil {
+			return nil, err
+		}
+	}
+	query := `
+		UPDATE locations SET name = $1, type = $2, address = $3, is_default = is_default OR $4, updated_at = $5
+		WHERE id = $6 AND deleted_at IS NULL
+		RETURNING id, name, type, address, is_default, created_at, updated_at
+	`
+	var updated domain.Location
+	err = tx.QueryRowContext(ctx, query, l.Name, string(l.Type), l.Address, l.IsDefault, time.Now(), id).Scan(
+		&updated.ID, &updated.Name, &updated.Type, &updated.Address, &updated.IsDefault, &updated.CreatedAt, &updated.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete soft-deletes a location. The default location and locations still
+// holding stock cannot be deleted.
+func (r *PostgresLocationRepository) Delete(ctx context.Context, id int) error {
+	var isDefault bool
+	var stock int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT l.is_default, COALESCE((SELECT SUM(quantity) FROM product_stocks WHERE location_id = l.id), 0)
+		FROM locations l WHERE l.id = $1 AND l.deleted_at IS NULL
+	`, id).Scan(&isDefault, &stock)
+	if err == sql.ErrNoRows {
+		return ErrLocationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		return errors.New("the default location cannot be deleted")
+	}
+	if stock != 0 {
+		return errors.New("location still holds stock; transfer it first")
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE locations SET deleted_at = $1 WHERE id = $2", time.Now(), id)
+	return err
+}
+
+// Transfer moves stock of one product between two locations, either of which
+// may be 0 for the default location. The total stock of the product does not
+// change.
+func (r *PostgresLocationRepository) Transfer(ctx context.Context, t domain.StockTransfer) (domain.StockTransfer, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.StockTransfer{}, err
+	}
+	defer tx.Rollback()
+
+	if t.FromLocationID, err = resolveLocation(ctx, tx, t.FromLocationID); err != nil {
+		return domain.StockTransfer{}, err
+	}
+	if t.ToLocationID, err = resolveLocation(ctx, tx, t.ToLocationID); err != nil {
+		return domain.StockTransfer{}, err
+	}
+	if _, err := lockProductStock(ctx, tx, t.ProductID, 0); err != nil {
+		return domain.StockTransfer{}, err
+	}
+	if err := adjustStock(ctx, tx, t.ProductID, t.FromLocationID, -t.Quantity); err != nil {
+		return domain.StockTransfer{}, err
+	}
+	if err := adjustStock(ctx, tx, t.ProductID, t.ToLocationID, t.Quantity); err != nil {
+		return domain.StockTransfer{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stock_transfers (product_id, from_location_id, to_location_id, quantity, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, t.ProductID, t.FromLocationID, t.ToLocationID, t.Quantity, t.Note, time.Now()).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return domain.StockTransfer{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.StockTransfer{}, err
+	}
+	return t, nil
+}
+
+func (r *PostgresLocationRepository) GetTransfers(ctx context.Context, productID int) ([]domain.StockTransfer, error) {
+	query := `
+		SELECT id, product_id, from_location_id, to_location_id, quantity, note, created_at
+		FROM stock_transfers
+		WHERE $1 = 0 OR product_id = $1
+		ORDER BY id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]domain.StockTransfer, 0)
+	for rows.Next() {
+		var t domain.StockTransfer
+		if err := rows.Scan(&t.ID, &t.ProductID, &t.FromLocationID, &t.ToLocationID, &t.Quantity, &t.Note, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
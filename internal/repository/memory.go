@@ -2,7 +2,7 @@ package repository
 
 import (
 	"cateogry-api/internal/domain"
-	"errors"
+	"context"
 	"time"
 )
 
@@ -20,20 +20,20 @@ func NewInMemoryCategoryRepository() *InMemoryCategoryRepository {
 	}
 }
 
-func (r *InMemoryCategoryRepository) GetAll() ([]domain.Category, error) {
+func (r *InMemoryCategoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
 	return r.categories, nil
 }
 
-func (r *InMemoryCategoryRepository) GetByID(id int) (*domain.Category, error) {
+func (r *InMemoryCategoryRepository) GetByID(ctx context.Context, id int) (*domain.Category, error) {
 	for _, c := range r.categories {
 		if c.ID == id {
 			return &c, nil
 		}
 	}
-	return nil, errors.New("category not found")
+	return nil, ErrCategoryNotFound
 }
 
-func (r *InMemoryCategoryRepository) Create(c domain.Category) (domain.Category, error) {
+func (r *InMemoryCategoryRepository) Create(ctx context.Context, c domain.Category) (domain.Category, error) {
 	maxID := 0
 	for _, cat := range r.categories {
 		if cat.ID > maxID {
@@ -47,7 +47,7 @@ func (r *InMemoryCategoryRepository) Create(c domain.Category) (domain.Category,
 	return c, nil
 }
 
-func (r *InMemoryCategoryRepository) Update(id int, u domain.Category) (*domain.Category, error) {
+func (r *InMemoryCategoryRepository) Update(ctx context.Context, id int, u domain.Category) (*domain.Category, error) {
 	for i, c := range r.categories {
 		if c.ID == id {
 			r.categories[i].Name = u.Name
@@ -56,15 +56,15 @@ func (r *InMemoryCategoryRepository) Update(id int, u domain.Category) (*domain.
 			return &r.categories[i], nil
 		}
 	}
-	return nil, errors.New("category not found")
+	return nil, ErrCategoryNotFound
 }
 
-func (r *InMemoryCategoryRepository) Delete(id int) error {
+func (r *InMemoryCategoryRepository) Delete(ctx context.Context, id int) error {
 	for i, c := range r.categories {
 		if c.ID == id {
 			r.categories = append(r.categories[:i], r.categories[i+1:]...)
 			return nil
 		}
 	}
-	return errors.New("category not found")
+	return ErrCategoryNotFound
 }
@@ -2,11 +2,14 @@ package repository
 
 import (
 	"cateogry-api/internal/domain"
+	"context"
 	"database/sql"
 	"errors"
 	"time"
 )
 
+var ErrCategoryNotFound = errors.New("category not found")
+
 type PostgresCategoryRepository struct {
 	db *sql.DB
 }
@@ -15,9 +18,9 @@ func NewPostgresCategoryRepository(db *sql.DB) *PostgresCategoryRepository {
 	return &PostgresCategoryRepository{db: db}
 }
 
-func (r *PostgresCategoryRepository) GetAll() ([]domain.Category, error) {
+func (r *PostgresCategoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
 	query := "SELECT id, name, description, created_at, updated_at FROM categories WHERE deleted_at IS NULL"
-	rows, err := r.db.Query(query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -34,12 +37,13 @@ func (r *PostgresCategoryRepository) GetAll() ([]domain.Category, error) {
 	return categories, nil
 }
 
-func (r *PostgresCategoryRepository) GetByID(id int) (*domain.Category, error) {
-	query := "SELECT id, name, description, created_at, updated_at FROM categories WHERE id = $1 AND deleted_at IS NULL"
+func (r *PostgresCategoryRepository) GetByID(ctx context.Context, id int) (*domain.Category, error) {
+	query := "SELECT id, name, description, created_at, updated_at FROM categories WHERE id = $1 AND deleted_at IS NULL" +
+		forUpdateInTx(ctx, "FOR UPDATE")
 	var c domain.Category
-	err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("category not found")
+		return nil, ErrCategoryNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -47,21 +51,21 @@ func (r *PostgresCategoryRepository) GetByID(id int) (*domain.Category, error) {
 	return &c, nil
 }
 
-func (r *PostgresCategoryRepository) Create(category domain.Category) (domain.Category, error) {
+func (r *PostgresCategoryRepository) Create(ctx context.Context, category domain.Category) (domain.Category, error) {
 	query := "INSERT INTO categories (name, description, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := r.db.QueryRow(query, category.Name, category.Description, time.Now(), time.Now()).Scan(&category.ID)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, category.Name, category.Description, time.Now(), time.Now()).Scan(&category.ID)
 	if err != nil {
 		return domain.Category{}, err
 	}
 	return category, nil
 }
 
-func (r *PostgresCategoryRepository) Update(id int, category domain.Category) (*domain.Category, error) {
+func (r *PostgresCategoryRepository) Update(ctx context.Context, id int, category domain.Category) (*domain.Category, error) {
 	query := "UPDATE categories SET name = $1, description = $2, updated_at = $3 WHERE id = $4 AND deleted_at IS NULL RETURNING id, name, description, created_at, updated_at"
 	var c domain.Category
-	err := r.db.QueryRow(query, category.Name, category.Description, time.Now(), id).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, category.Name, category.Description, time.Now(), id).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("category not found")
+		return nil, ErrCategoryNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -69,9 +73,9 @@ func (r *PostgresCategoryRepository) Update(id int, category domain.Category) (*
 	return &c, nil
 }
 
-func (r *PostgresCategoryRepository) Delete(id int) error {
+func (r *PostgresCategoryRepository) Delete(ctx context.Context, id int) error {
 	query := "UPDATE categories SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
-	result, err := r.db.Exec(query, time.Now(), id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
 		return err
 	}
@@ -80,14 +84,14 @@ func (r *PostgresCategoryRepository) Delete(id int) error {
 		return err
 	}
 	if rows == 0 {
-		return errors.New("category not found")
+		return ErrCategoryNotFound
 	}
 	return nil
 }
 
-func (r *PostgresCategoryRepository) CleanUpOldDeleted(duration time.Duration) error {
+func (r *PostgresCategoryRepository) CleanUpOldDeleted(ctx context.Context, duration time.Duration) error {
 	threshold := time.Now().Add(-duration)
 	query := "DELETE FROM categories WHERE deleted_at < $1"
-	_, err := r.db.Exec(query, threshold)
+	_, err := r.db.ExecContext(ctx, query, threshold)
 	return err
 }
@@ -0,0 +1,592 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/testdb"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPostgresCategoryRepository(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresCategoryRepository(db)
+
+	created, err := r.Create(ctx, domain.Category{Name: "Toys", Description: "For kids"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := r.Update(ctx, created.ID, domain.Category{Name: "Games", Description: "For everyone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Games" || updated.Description != "For everyone" {
+		t.Errorf("Update = %+v", updated)
+	}
+	if got, err := r.GetByID(ctx, created.ID); err != nil || got.Description != "For everyone" {
+		t.Errorf("GetByID = %+v, %v", got, err)
+	}
+
+	if err := r.Delete(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, created.ID); err == nil {
+		t.Error("GetByID of a deleted category returned no error")
+	}
+	if err := r.Delete(ctx, created.ID); err == nil {
+		t.Error("Delete of a deleted category returned no error")
+	}
+	all, err := r.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range all {
+		if c.ID == created.ID {
+			t.Error("GetAll lists a deleted category")
+		}
+	}
+
+	// Deleted rows stay until they are older than the retention
+	if err := r.CleanUpOldDeleted(ctx, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, "SELECT COUNT(*) FROM categories WHERE id = $1", created.ID); n != 1 {
+		t.Errorf("category purged before its retention ended")
+	}
+	if err := r.CleanUpOldDeleted(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, "SELECT COUNT(*) FROM categories WHERE id = $1", created.ID); n != 0 {
+		t.Errorf("category not purged after its retention ended")
+	}
+}
+
+// newStockedProduct creates a category and a product in it with stock at
+// the default location.
+func newStockedProduct(t *testing.T, db *sql.DB, price, stock int) domain.Product {
+	t.Helper()
+	ctx := context.Background()
+	c, err := NewPostgresCategoryRepository(db).Create(ctx, domain.Category{Name: "Electronics"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewPostgresProductRepository(db).Create(ctx, domain.Product{
+		Name: "Wireless Mouse", Price: price, CostPrice: price / 2, Stock: stock, CategoryID: c.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPostgresProductRepository(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresProductRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+
+	got, err := r.GetByID(ctx, p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Stock != 5 || got.CategoryName != "Electronics" {
+		t.Errorf("GetByID = stock %d, category %q; want 5, Electronics", got.Stock, got.CategoryName)
+	}
+
+	got.Stock, got.Price = 8, 175000
+	if _, err := r.Update(ctx, p.ID, *got); err != nil {
+		t.Fatal(err)
+	}
+	found, err := r.GetAll(ctx, "MOUSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Stock != 8 || found[0].Price != 175000 {
+		t.Errorf("GetAll(MOUSE) = %+v, want the updated mouse", found)
+	}
+	inCategory, err := r.GetByCategory(ctx, p.CategoryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inCategory) != 1 || inCategory[0].ID != p.ID {
+		t.Errorf("GetByCategory(%d) = %+v, want the mouse", p.CategoryID, inCategory)
+	}
+	if _, err := r.GetByCategory(ctx, p.CategoryID+1); !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("GetByCategory of a missing category: err = %v, want %v", err, ErrCategoryNotFound)
+	}
+
+	if err := r.Delete(ctx, p.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, p.ID); err == nil {
+		t.Error("GetByID of a deleted product returned no error")
+	}
+}
+
+func TestPostgresProductCleanUpKeepsHistory(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresProductRepository(db)
+	moved := newStockedProduct(t, db, 150000, 5)
+	unused := newStockedProduct(t, db, 150000, 5)
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO stock_transfers (product_id, from_location_id, to_location_id, quantity)
+		SELECT $1, id, id, 1 FROM locations WHERE is_default
+	`, moved.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []domain.Product{moved, unused} {
+		if err := r.Delete(ctx, p.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.CleanUpOldDeleted(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	var remaining []int
+	rows, err := db.QueryContext(ctx, "SELECT id FROM products ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		remaining = append(remaining, id)
+	}
+	if len(remaining) != 1 || remaining[0] != moved.ID {
+		t.Errorf("products after clean-up = %v, want only %d, which has a transfer", remaining, moved.ID)
+	}
+}
+
+func TestPostgresCheckout(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresTransactionRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+
+	tx, err := r.CreateTransaction(ctx, domain.CheckoutRequest{
+		Items: []domain.CheckoutItem{{ProductID: p.ID, Quantity: 2}},
+	}, func(t *domain.Transaction) error {
+		t.TaxAmount = 33000
+		t.TotalAmount += t.TaxAmount
+		t.Payments = []domain.Payment{{Method: domain.PaymentCash, Amount: t.TotalAmount, Tendered: 350000, Change: 350000 - t.TotalAmount}}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Subtotal != 300000 || tx.TotalAmount != 333000 {
+		t.Errorf("CreateTransaction = subtotal %d, total %d; want 300000, 333000", tx.Subtotal, tx.TotalAmount)
+	}
+
+	got, err := r.GetByID(ctx, tx.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Details) != 1 || got.Details[0].ProductName != "Wireless Mouse" || len(got.Payments) != 1 || got.Payments[0].Change != 17000 {
+		t.Errorf("GetByID = %+v", got)
+	}
+	if stock, _ := NewPostgresProductRepository(db).GetByID(ctx, p.ID); stock.Stock != 3 {
+		t.Errorf("stock after checkout = %d, want 3", stock.Stock)
+	}
+
+	_, err = r.CreateTransaction(ctx, domain.CheckoutRequest{
+		Items: []domain.CheckoutItem{{ProductID: p.ID, Quantity: 4}},
+	}, nil)
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("checkout of more than the stock: error = %v, want %v", err, ErrInsufficientStock)
+	}
+
+	failed := errors.New("declined")
+	_, err = r.CreateTransaction(ctx, domain.CheckoutRequest{
+		Items: []domain.CheckoutItem{{ProductID: p.ID, Quantity: 1}},
+	}, func(*domain.Transaction) error { return failed })
+	if !errors.Is(err, failed) {
+		t.Errorf("CreateTransaction error = %v, want %v", err, failed)
+	}
+	if stock, _ := NewPostgresProductRepository(db).GetByID(ctx, p.ID); stock.Stock != 3 {
+		t.Errorf("stock after a failed checkout = %d, want 3", stock.Stock)
+	}
+}
+
+func TestPostgresCheckoutLockOrder(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresTransactionRepository(db)
+	a := newStockedProduct(t, db, 150000, 5)
+	b := newStockedProduct(t, db, 90000, 5)
+
+	// Another checkout of [A, B] holds A and is about to lock B
+	holder, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Rollback()
+	if _, err := holder.Exec("SELECT 1 FROM products WHERE id = $1 FOR UPDATE", a.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.CreateTransaction(ctx, domain.CheckoutRequest{
+			Items: []domain.CheckoutItem{{ProductID: b.ID, Quantity: 1}, {ProductID: a.ID, Quantity: 1}, {ProductID: b.ID, Quantity: 1}},
+		}, nil)
+		done <- err
+	}()
+	for countRows(t, db, "SELECT COUNT(*) FROM pg_stat_activity WHERE datname = current_database() AND wait_event_type = 'Lock'") == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The checkout of [B, A] waits for A without holding B, so this does not deadlock
+	if _, err := holder.Exec("SELECT 1 FROM products WHERE id = $1 FOR UPDATE", b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := holder.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if stock, _ := NewPostgresProductRepository(db).GetByID(ctx, b.ID); stock.Stock != 3 {
+		t.Errorf("stock of B after checkout = %d, want 3", stock.Stock)
+	}
+}
+
+func TestPostgresCheckoutReservation(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresTransactionRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+	res, err := NewPostgresReservationRepository(db).Create(ctx, []domain.CheckoutItem{{ProductID: p.ID, Quantity: 2}}, time.Minute, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkout := func(caller string, quantity int) error {
+		_, err := r.CreateTransaction(ctx, domain.CheckoutRequest{
+			Items:         []domain.CheckoutItem{{ProductID: p.ID, Quantity: quantity}},
+			ReservationID: res.ID,
+			Caller:        caller,
+		}, nil)
+		return err
+	}
+	if err := checkout("bob", 2); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("checkout of another caller's reservation: error = %v, want %v", err, ErrReservationNotFound)
+	}
+	if err := checkout("alice", 3); !errors.Is(err, ErrNotReserved) {
+		t.Errorf("checkout of more than reserved: error = %v, want %v", err, ErrNotReserved)
+	}
+	if err := checkout("alice", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkout("alice", 1); !errors.Is(err, ErrReservationNotActive) {
+		t.Errorf("checkout of a consumed reservation: error = %v, want %v", err, ErrReservationNotActive)
+	}
+}
+
+func TestPostgresCheckoutCart(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresTransactionRepository(db)
+	carts := NewPostgresCartRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+	cart, err := carts.Create(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := domain.CheckoutRequest{CartID: cart.ID}
+	if _, err := r.CreateTransaction(ctx, req, nil); !errors.Is(err, ErrCartEmpty) {
+		t.Errorf("checkout of an empty cart: error = %v, want %v", err, ErrCartEmpty)
+	}
+	if err := carts.AddItem(ctx, cart.ID, p.ID, 2, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := errors.New("declined")
+	if _, err := r.CreateTransaction(ctx, req, func(*domain.Transaction) error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("CreateTransaction error = %v, want %v", err, failed)
+	}
+	if got, _ := carts.GetByID(ctx, cart.ID); got.Status != domain.CartOpen {
+		t.Errorf("cart status after a failed checkout = %s, want %s", got.Status, domain.CartOpen)
+	}
+
+	tx, err := r.CreateTransaction(ctx, req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := carts.GetByID(ctx, cart.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != domain.CartCheckedOut || got.TransactionID == nil || *got.TransactionID != tx.ID {
+		t.Errorf("cart after checkout = %s, transaction %v; want %s, %d", got.Status, got.TransactionID, domain.CartCheckedOut, tx.ID)
+	}
+	if tx.Subtotal != 300000 {
+		t.Errorf("cart checkout subtotal = %d, want 300000", tx.Subtotal)
+	}
+	if _, err := r.CreateTransaction(ctx, req, nil); !errors.Is(err, ErrCartNotFound) {
+		t.Errorf("second checkout of a cart: error = %v, want %v", err, ErrCartNotFound)
+	}
+}
+
+func TestPostgresCheckoutCartDeletedProduct(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	carts := NewPostgresCartRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+	cart, err := carts.Create(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := carts.AddItem(ctx, cart.ID, p.ID, 1, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewPostgresProductRepository(db).Delete(ctx, p.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewPostgresTransactionRepository(db).CreateTransaction(ctx, domain.CheckoutRequest{CartID: cart.ID}, nil)
+	if !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("checkout of a cart with a deleted product: error = %v, want %v", err, ErrProductNotFound)
+	}
+}
+
+func TestPostgresStockTransfer(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresLocationRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+	warehouse, err := r.Create(ctx, domain.Location{Name: "Back Warehouse", Type: domain.LocationWarehouse})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0 is the default location
+	transfer, err := r.Transfer(ctx, domain.StockTransfer{ProductID: p.ID, ToLocationID: warehouse.ID, Quantity: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transfer.FromLocationID == 0 {
+		t.Error("Transfer from location 0 recorded location 0, want the default location")
+	}
+	if n := countRows(t, db, "SELECT COALESCE(SUM(quantity), 0) FROM product_stocks WHERE product_id = $1 AND location_id = $2", p.ID, warehouse.ID); n != 2 {
+		t.Errorf("warehouse stock after transfer = %d, want 2", n)
+	}
+	if _, err := r.Transfer(ctx, domain.StockTransfer{ProductID: p.ID, ToLocationID: warehouse.ID, Quantity: 4}); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("transfer of more than the stock: error = %v, want %v", err, ErrInsufficientStock)
+	}
+}
+
+func TestPostgresPurchaseOrderReceive(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresPurchaseOrderRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+	supplier, err := NewPostgresSupplierRepository(db).Create(ctx, domain.Supplier{Name: "Logi Distributor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	po, err := r.Create(ctx, domain.PurchaseOrder{
+		SupplierID: supplier.ID,
+		Items:      []domain.PurchaseOrderItem{{ProductID: p.ID, QuantityOrdered: 5, UnitCost: 90000}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Receive(ctx, po.ID, []domain.CheckoutItem{{ProductID: p.ID, Quantity: 1}}); !errors.Is(err, ErrPurchaseOrderStatus) {
+		t.Errorf("Receive on a draft: error = %v, want %v", err, ErrPurchaseOrderStatus)
+	}
+	if _, err := r.MarkOrdered(ctx, po.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Update(ctx, po.ID, *po); !errors.Is(err, ErrPurchaseOrderStatus) {
+		t.Errorf("Update of an ordered purchase order: error = %v, want %v", err, ErrPurchaseOrderStatus)
+	}
+	if err := r.Delete(ctx, po.ID); !errors.Is(err, ErrPurchaseOrderStatus) {
+		t.Errorf("Delete of an ordered purchase order: error = %v, want %v", err, ErrPurchaseOrderStatus)
+	}
+	if err := r.Delete(ctx, po.ID+1000); !errors.Is(err, ErrPurchaseOrderNotFound) {
+		t.Errorf("Delete of an unknown purchase order: error = %v, want %v", err, ErrPurchaseOrderNotFound)
+	}
+	if _, err := r.Receive(ctx, po.ID, []domain.CheckoutItem{{ProductID: p.ID, Quantity: 6}}); !errors.Is(err, ErrInvalidReceipt) {
+		t.Errorf("Receive of more than ordered: error = %v, want %v", err, ErrInvalidReceipt)
+	}
+
+	if _, err := r.Receive(ctx, po.ID, []domain.CheckoutItem{{ProductID: p.ID, Quantity: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewPostgresProductRepository(db).GetByID(ctx, p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5 on hand at 75000 and 5 received at 90000
+	if got.Stock != 10 || got.CostPrice != 82500 {
+		t.Errorf("after receiving = stock %d, cost price %d; want 10, 82500", got.Stock, got.CostPrice)
+	}
+}
+
+func TestPostgresCheckoutIdempotencyKey(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresTransactionRepository(db)
+	p := newStockedProduct(t, db, 150000, 10)
+	req := domain.CheckoutRequest{
+		Items:          []domain.CheckoutItem{{ProductID: p.ID, Quantity: 1}},
+		IdempotencyKey: "till-1-0042",
+		Caller:         "api_key:1",
+		RequestHash:    "a",
+	}
+
+	// Concurrent attempts with one key create a single transaction
+	const attempts = 4
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	for i := range attempts {
+		wg.Go(func() {
+			_, errs[i] = r.CreateTransaction(ctx, req, nil)
+		})
+	}
+	wg.Wait()
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrIdempotencyKeyReplayed):
+			t.Errorf("CreateTransaction error = %v, want nil or %v", err, ErrIdempotencyKeyReplayed)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d attempts created %d transactions, want 1", attempts, created)
+	}
+
+	otherCaller, otherRequest := req, req
+	otherCaller.Caller, otherRequest.RequestHash = "api_key:2", "b"
+	for _, reuse := range []domain.CheckoutRequest{otherCaller, otherRequest} {
+		if _, err := r.CreateTransaction(ctx, reuse, nil); !errors.Is(err, ErrIdempotencyKeyUsed) {
+			t.Errorf("reuse of a key by %s for %s: error = %v, want %v", reuse.Caller, reuse.RequestHash, err, ErrIdempotencyKeyUsed)
+		}
+	}
+
+	first, err := r.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.TotalAmount != 150000 {
+		t.Errorf("GetByIdempotencyKey total = %d, want 150000", first.TotalAmount)
+	}
+	if stock, _ := NewPostgresProductRepository(db).GetByID(ctx, p.ID); stock.Stock != 9 {
+		t.Errorf("stock = %d, want 9", stock.Stock)
+	}
+	if _, err := r.GetByIdempotencyKey(ctx, "unused"); err == nil {
+		t.Error("GetByIdempotencyKey of an unused key returned no error")
+	}
+}
+
+func TestPostgresReport(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	r := NewPostgresTransactionRepository(db)
+	p := newStockedProduct(t, db, 150000, 10)
+
+	for _, qty := range []int{1, 3} {
+		if _, err := r.CreateTransaction(ctx, domain.CheckoutRequest{
+			Items: []domain.CheckoutItem{{ProductID: p.ID, Quantity: qty}},
+		}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := r.GetDailyReport(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalTransactions != 2 || report.TotalRevenue != 600000 {
+		t.Errorf("report = %d transactions, %d revenue; want 2, 600000", report.TotalTransactions, report.TotalRevenue)
+	}
+	if report.BestSellingProduct.Name != "Wireless Mouse" || report.BestSellingProduct.QtySold != 4 {
+		t.Errorf("best seller = %+v, want 4 Wireless Mouse", report.BestSellingProduct)
+	}
+	if report.TotalCOGS != 300000 || report.GrossProfit != 300000 {
+		t.Errorf("COGS/gross profit = %d/%d, want 300000/300000", report.TotalCOGS, report.GrossProfit)
+	}
+
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	report, err = r.GetReport(ctx, lastWeek, lastWeek.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalTransactions != 0 {
+		t.Errorf("report of last week has %d transactions, want 0", report.TotalTransactions)
+	}
+}
+
+func TestPostgresAuditRunInTx(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	products := NewPostgresProductRepository(db)
+	audit := NewPostgresAuditRepository(db)
+	p := newStockedProduct(t, db, 150000, 5)
+
+	update := func(price int, result error) error {
+		return audit.RunInTx(ctx, func(ctx context.Context) error {
+			before, err := products.GetByID(ctx, p.ID)
+			if err != nil {
+				return err
+			}
+			changed := *before
+			changed.Price = price
+			if _, err := products.Update(ctx, p.ID, changed); err != nil {
+				return err
+			}
+			entry := domain.AuditEntry{Actor: "alice", Action: domain.AuditUpdate, EntityType: "product", EntityID: p.ID}
+			if err := audit.Create(ctx, entry); err != nil {
+				return err
+			}
+			return result
+		})
+	}
+	check := func(wantPrice, wantEntries int) {
+		t.Helper()
+		got, err := products.GetByID(ctx, p.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries, err := audit.Find(ctx, domain.AuditFilter{EntityType: "product", Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Price != wantPrice || len(entries) != wantEntries {
+			t.Errorf("price %d with %d audit entries, want %d with %d", got.Price, len(entries), wantPrice, wantEntries)
+		}
+	}
+
+	failed := errors.New("declined")
+	if err := update(175000, failed); !errors.Is(err, failed) {
+		t.Fatalf("RunInTx error = %v, want %v", err, failed)
+	}
+	check(150000, 0)
+
+	if err := update(175000, nil); err != nil {
+		t.Fatal(err)
+	}
+	check(175000, 1)
+}
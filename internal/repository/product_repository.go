@@ -2,109 +2,224 @@ package repository
 
 import (
 	"cateogry-api/internal/domain"
+	"context"
 	"database/sql"
 	"errors"
 	"time"
 )
 
-type ProductRepository struct {
+var ErrProductNotFound = errors.New("product not found")
+
+type PostgresProductRepository struct {
 	db *sql.DB
 }
 
-func NewProductRepository(db *sql.DB) *ProductRepository {
-	return &ProductRepository{db: db}
+func NewPostgresProductRepository(db *sql.DB) *PostgresProductRepository {
+	return &PostgresProductRepository{db: db}
+}
+
+func (r *PostgresProductRepository) GetAll(ctx context.Context, name string) ([]domain.Product, error) {
+	if name == "" {
+		return r.list(ctx, "")
+	}
+	return r.list(ctx, "AND p.name ILIKE $1", "%"+name+"%")
+}
+
+// GetByCategory returns the products of a category, or ErrCategoryNotFound
+// when there is no such category.
+func (r *PostgresProductRepository) GetByCategory(ctx context.Context, categoryID int) ([]domain.Product, error) {
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1 AND deleted_at IS NULL)", categoryID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCategoryNotFound
+	}
+	return r.list(ctx, "AND p.category_id = $1", categoryID)
 }
 
-func (r *ProductRepository) GetAll(name string) ([]domain.Product, error) {
+// list returns the products that are not deleted and match filter, a
+// condition appended to the WHERE clause with args as its parameters.
+func (r *PostgresProductRepository) list(ctx context.Context, filter string, args ...any) ([]domain.Product, error) {
 	query := `
-		SELECT p.id, p.name, p.description, p.price, p.stock, p.category_id, 
+		SELECT p.id, p.name, p.description, p.price, p.cost_price, p.stock, p.stock - ` + reservedStockSQL + `, p.category_id,
 		       p.created_at, p.updated_at, c.name as category_name
 		FROM products p
 		JOIN categories c ON p.category_id = c.id
-		WHERE p.deleted_at IS NULL
+		WHERE p.deleted_at IS NULL ` + filter + `
+		ORDER BY p.id
 	`
-
-	args := []interface{}{}
-	if name != "" {
-		query += " AND p.name ILIKE $1"
-		args = append(args, "%"+name+"%")
-	}
-
-	rows, err := r.db.Query(query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var products []domain.Product
+	products := make([]domain.Product, 0)
 	for rows.Next() {
 		var p domain.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.Stock, &p.CategoryID, &p.CreatedAt, &p.UpdatedAt, &p.CategoryName); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.CostPrice, &p.Stock, &p.AvailableStock, &p.CategoryID, &p.CreatedAt, &p.UpdatedAt, &p.CategoryName); err != nil {
 			return nil, err
 		}
 		products = append(products, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadLocations(ctx, products); err != nil {
+		return nil, err
+	}
 	return products, nil
 }
 
-func (r *ProductRepository) GetByID(id int) (*domain.Product, error) {
+func (r *PostgresProductRepository) GetByID(ctx context.Context, id int) (*domain.Product, error) {
 	query := `
-		SELECT p.id, p.name, p.description, p.price, p.stock, p.category_id, 
+		SELECT p.id, p.name, p.description, p.price, p.cost_price, p.stock, p.stock - ` + reservedStockSQL + `, p.category_id,
 		       p.created_at, p.updated_at, c.name as category_name
 		FROM products p
 		JOIN categories c ON p.category_id = c.id
 		WHERE p.id = $1 AND p.deleted_at IS NULL
-	`
+	` + forUpdateInTx(ctx, "FOR UPDATE OF p")
 	var p domain.Product
-	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.Stock, &p.CategoryID, &p.CreatedAt, &p.UpdatedAt, &p.CategoryName)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.CostPrice, &p.Stock, &p.AvailableStock, &p.CategoryID, &p.CreatedAt, &p.UpdatedAt, &p.CategoryName)
 	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
+		return nil, ErrProductNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &p, nil
+	products := []domain.Product{p}
+	if err := r.loadLocations(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
-func (r *ProductRepository) Create(product domain.Product) (domain.Product, error) {
+// Create inserts a product. Its initial stock is put at the default location.
+func (r *PostgresProductRepository) Create(ctx context.Context, product domain.Product) (domain.Product, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO products (name, description, price, stock, category_id, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7) 
-		RETURNING id, created_at, updated_at
+		INSERT INTO products (name, description, price, cost_price, stock, category_id, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, 0, $5, $6, $7) 
+		RETURNING id
 	`
 	now := time.Now()
-	err := r.db.QueryRow(query, product.Name, product.Description, product.Price, product.Stock, product.CategoryID, now, now).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
+	var id int
+	err = tx.QueryRowContext(ctx, query, product.Name, product.Description, product.Price, product.CostPrice, product.CategoryID, now, now).Scan(&id)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if err := r.adjustDefaultStock(ctx, tx.Tx, id, product.Stock); err != nil {
+		return domain.Product{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.Product{}, err
+	}
+
+	created, err := r.GetByID(ctx, id)
 	if err != nil {
 		return domain.Product{}, err
 	}
-	return product, nil
+	return *created, nil
 }
 
-func (r *ProductRepository) Update(id int, product domain.Product) (*domain.Product, error) {
+// Update changes a product. Stock is the total over all locations, so a
+// change to it is applied to the default location; use stock transfers to
+// move stock between locations.
+func (r *PostgresProductRepository) Update(ctx context.Context, id int, product domain.Product) (*domain.Product, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var stock int
+	err = tx.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE products 
-		SET name = $1, description = $2, price = $3, stock = $4, category_id = $5, updated_at = $6 
-		WHERE id = $7 AND deleted_at IS NULL
-		RETURNING id, name, description, price, stock, category_id, created_at, updated_at
+		SET name = $1, description = $2, price = $3, cost_price = $4, category_id = $5, updated_at = $6 
+		WHERE id = $7
 	`
-	var p domain.Product
-	err := r.db.QueryRow(query, product.Name, product.Description, product.Price, product.Stock, product.CategoryID, time.Now(), id).Scan(
-		&p.ID, &p.Name, &p.Description, &p.Price, &p.Stock, &p.CategoryID, &p.CreatedAt, &p.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return nil, errors.New("product not found")
-	}
+	_, err = tx.ExecContext(ctx, query, product.Name, product.Description, product.Price, product.CostPrice, product.CategoryID, time.Now(), id)
 	if err != nil {
 		return nil, err
 	}
-	// We need to fetch CategoryName separately or assume it hasn't changed drastically,
-	// but simpler to return without it for update or do another query if strictly needed.
-	// For now let's keep it simple.
-	return &p, nil
+	if err := r.adjustDefaultStock(ctx, tx.Tx, id, product.Stock-stock); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *PostgresProductRepository) adjustDefaultStock(ctx context.Context, tx *sql.Tx, productID, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	locationID, err := resolveLocation(ctx, tx, 0)
+	if err != nil {
+		return err
+	}
+	return adjustStock(ctx, tx, productID, locationID, delta)
 }
 
-func (r *ProductRepository) Delete(id int) error {
+// loadLocations fills in the per-location stock of products, reading only
+// their levels.
+func (r *PostgresProductRepository) loadLocations(ctx context.Context, products []domain.Product) error {
+	index := make(map[int]int, len(products))
+	ids := make([]int, 0, len(products))
+	for i := range products {
+		index[products[i].ID] = i
+		ids = append(ids, products[i].ID)
+		products[i].Locations = make([]domain.ProductStock, 0)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT ps.product_id, ps.location_id, l.name, ps.quantity
+		FROM product_stocks ps
+		JOIN locations l ON ps.location_id = l.id
+		WHERE l.deleted_at IS NULL AND ps.product_id = ANY($1)
+		ORDER BY l.id
+	`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var ps domain.ProductStock
+		if err := rows.Scan(&id, &ps.LocationID, &ps.LocationName, &ps.Quantity); err != nil {
+			return err
+		}
+		if i, ok := index[id]; ok {
+			products[i].Locations = append(products[i].Locations, ps)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *PostgresProductRepository) Delete(ctx context.Context, id int) error {
 	query := "UPDATE products SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
-	result, err := r.db.Exec(query, time.Now(), id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
 		return err
 	}
@@ -113,14 +228,23 @@ func (r *ProductRepository) Delete(id int) error {
 		return err
 	}
 	if rows == 0 {
-		return errors.New("product not found")
+		return ErrProductNotFound
 	}
 	return nil
 }
 
-func (r *ProductRepository) CleanUpOldDeleted(duration time.Duration) error {
+// CleanUpOldDeleted purges products deleted more than duration ago. Products
+// with stock transfers or purchase orders are kept, so that history stays
+// complete.
+func (r *PostgresProductRepository) CleanUpOldDeleted(ctx context.Context, duration time.Duration) error {
 	threshold := time.Now().Add(-duration)
-	query := "DELETE FROM products WHERE deleted_at < $1"
-	_, err := r.db.Exec(query, threshold)
+	query := `
+		DELETE FROM products p
+		WHERE p.deleted_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM stock_transfers WHERE product_id = p.id)
+		  AND NOT EXISTS (SELECT 1 FROM purchase_order_items WHERE product_id = p.id)
+		  AND NOT EXISTS (SELECT 1 FROM stock_receipts WHERE product_id = p.id)
+	`
+	_, err := r.db.ExecContext(ctx, query, threshold)
 	return err
 }
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type PostgresPromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPromotionRepository(db *sql.DB) *PostgresPromotionRepository {
+	return &PostgresPromotionRepository{db: db}
+}
+
+const promotionColumns = `id, code, name, type, value, product_id, category_id, buy_quantity, get_quantity,
+		stackable, priority, active, starts_at, ends_at, created_at, updated_at`
+
+func scanPromotion(row interface{ Scan(...any) error }) (domain.Promotion, error) {
+	var p domain.Promotion
+	err := row.Scan(&p.ID, &p.Code, &p.Name, &p.Type, &p.Value, &p.ProductID, &p.CategoryID, &p.BuyQuantity, &p.GetQuantity,
+		&p.Stackable, &p.Priority, &p.Active, &p.StartsAt, &p.EndsAt, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+func (r *PostgresPromotionRepository) queryPromotions(ctx context.Context, query string, args ...any) ([]domain.Promotion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]domain.Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, p)
+	}
+	return promotions, rows.Err()
+}
+
+func (r *PostgresPromotionRepository) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	query := "SELECT " + promotionColumns + " FROM promotions WHERE deleted_at IS NULL ORDER BY id"
+	return r.queryPromotions(ctx, query)
+}
+
+// GetValidAt returns every active, non-deleted promotion whose validity window
+// contains t, both automatic and code-based.
+func (r *PostgresPromotionRepository) GetValidAt(ctx context.Context, t time.Time) ([]domain.Promotion, error) {
+	query := "SELECT " + promotionColumns + ` FROM promotions
+		WHERE deleted_at IS NULL AND active = TRUE
+		  AND (starts_at IS NULL OR starts_at <= $1)
+		  AND (ends_at IS NULL OR ends_at > $1)
+		ORDER BY priority DESC, id`
+	return r.queryPromotions(ctx, query, t)
+}
+
+func (r *PostgresPromotionRepository) GetByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	query := "SELECT " + promotionColumns + " FROM promotions WHERE id = $1 AND deleted_at IS NULL"
+	p, err := scanPromotion(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("promotion not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PostgresPromotionRepository) Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	query := `
+		INSERT INTO promotions (code, name, type, value, product_id, category_id, buy_quantity, get_quantity,
+			stackable, priority, active, starts_at, ends_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at, updated_at
+	`
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query, p.Code, p.Name, string(p.Type), p.Value, p.ProductID, p.CategoryID, p.BuyQuantity, p.GetQuantity,
+		p.Stackable, p.Priority, p.Active, p.StartsAt, p.EndsAt, now, now).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	return p, nil
+}
+
+func (r *PostgresPromotionRepository) Update(ctx context.Context, id int, p domain.Promotion) (*domain.Promotion, error) {
+	query := `
+		UPDATE promotions
+		SET code = $1, name = $2, type = $3, value = $4, product_id = $5, category_id = $6, buy_quantity = $7,
+		    get_quantity = $8, stackable = $9, priority = $10, active = $11, starts_at = $12, ends_at = $13, updated_at = $14
+		WHERE id = $15 AND deleted_at IS NULL
+		RETURNING ` + promotionColumns
+	updated, err := scanPromotion(r.db.QueryRowContext(ctx, query, p.Code, p.Name, string(p.Type), p.Value, p.ProductID, p.CategoryID, p.BuyQuantity,
+		p.GetQuantity, p.Stackable, p.Priority, p.Active, p.StartsAt, p.EndsAt, time.Now(), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("promotion not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *PostgresPromotionRepository) Delete(ctx context.Context, id int) error {
+	query := "UPDATE promotions SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("promotion not found")
+	}
+	return nil
+}
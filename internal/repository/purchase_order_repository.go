@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+	// ErrPurchaseOrderStatus is wrapped when an order's status does not
+	// allow the change, such as editing an order that was already placed.
+	ErrPurchaseOrderStatus = errors.New("purchase order status does not allow this")
+	// ErrInvalidReceipt is wrapped when received items are not on the order
+	// or exceed what is still outstanding.
+	ErrInvalidReceipt = errors.New("receipt does not match the purchase order")
+)
+
+type PostgresPurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPurchaseOrderRepository(db *sql.DB) *PostgresPurchaseOrderRepository {
+	return &PostgresPurchaseOrderRepository{db: db}
+}
+
+const purchaseOrderSelect = `
+	SELECT po.id, po.supplier_id, s.name, po.location_id, po.status, po.note,
+	       po.ordered_at, po.received_at, po.created_at, po.updated_at
+	FROM purchase_orders po
+	JOIN suppliers s ON po.supplier_id = s.id
+`
+
+func scanPurchaseOrder(row interface{ Scan(...any) error }) (domain.PurchaseOrder, error) {
+	var po domain.PurchaseOrder
+	err := row.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.LocationID, &po.Status, &po.Note,
+		&po.OrderedAt, &po.ReceivedAt, &po.CreatedAt, &po.UpdatedAt)
+	return po, err
+}
+
+// GetAll returns purchase orders, newest first, optionally filtered by status.
+func (r *PostgresPurchaseOrderRepository) GetAll(ctx context.Context, status domain.PurchaseOrderStatus) ([]domain.PurchaseOrder, error) {
+	rows, err := r.db.QueryContext(ctx, purchaseOrderSelect+" WHERE $1 = '' OR po.status = $1 ORDER BY po.id DESC", string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]domain.PurchaseOrder, 0)
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		if err := r.loadItems(ctx, &orders[i]); err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+func (r *PostgresPurchaseOrderRepository) GetByID(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	po, err := scanPurchaseOrder(r.db.QueryRowContext(ctx, purchaseOrderSelect+" WHERE po.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, &po); err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+func (r *PostgresPurchaseOrderRepository) loadItems(ctx context.Context, po *domain.PurchaseOrder) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT poi.id, poi.product_id, p.name, poi.quantity_ordered, poi.quantity_received, poi.unit_cost
+		FROM purchase_order_items poi
+		JOIN products p ON poi.product_id = p.id
+		WHERE poi.purchase_order_id = $1
+		ORDER BY poi.id
+	`, po.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	po.Items = make([]domain.PurchaseOrderItem, 0)
+	po.TotalCost = 0
+	for rows.Next() {
+		var item domain.PurchaseOrderItem
+		if err := rows.Scan(&item.ID, &item.ProductID, &item.ProductName, &item.QuantityOrdered, &item.QuantityReceived, &item.UnitCost); err != nil {
+			return err
+		}
+		po.TotalCost += item.QuantityOrdered * item.UnitCost
+		po.Items = append(po.Items, item)
+	}
+	return rows.Err()
+}
+
+func (r *PostgresPurchaseOrderRepository) Create(ctx context.Context, po domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	locationID, err := resolveLocation(ctx, tx, po.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO purchase_orders (supplier_id, location_id, status, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id
+	`, po.SupplierID, locationID, string(domain.PurchaseOrderDraft), po.Note, now).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertPurchaseOrderItems(ctx, tx, id, po.Items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Update replaces the supplier, location, note and items of a draft order.
+func (r *PostgresPurchaseOrderRepository) Update(ctx context.Context, id int, po domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(ctx, tx, id, domain.PurchaseOrderDraft); err != nil {
+		return nil, err
+	}
+	locationID, err := resolveLocation(ctx, tx, po.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE purchase_orders SET supplier_id = $1, location_id = $2, note = $3, updated_at = $4 WHERE id = $5",
+		po.SupplierID, locationID, po.Note, time.Now(), id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM purchase_order_items WHERE purchase_order_id = $1", id); err != nil {
+		return nil, err
+	}
+	if err := insertPurchaseOrderItems(ctx, tx, id, po.Items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Delete removes a draft order. Orders past draft are kept for their history
+// and fail with ErrPurchaseOrderStatus.
+func (r *PostgresPurchaseOrderRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockPurchaseOrder(ctx, tx, id, domain.PurchaseOrderDraft); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM purchase_orders WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkOrdered moves a draft order to ordered.
+func (r *PostgresPurchaseOrderRepository) MarkOrdered(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	now := time.Now()
+	result, err := r.db.ExecContext(ctx, "UPDATE purchase_orders SET status = $1, ordered_at = $2, updated_at = $2 WHERE id = $3 AND status = $4",
+		string(domain.PurchaseOrderOrdered), now, id, string(domain.PurchaseOrderDraft))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: purchase order %d is not a draft", ErrPurchaseOrderStatus, id)
+	}
+	return r.GetByID(ctx, id)
+}
+
+// Receive books arrived quantities into stock at the order's location, using
+// the same stock adjustment as checkout, and records each receipt with its
+// cost price. Each product's cost price becomes the weighted average of its
+// stock on hand and the received units. The order becomes received once every
+// line is complete.
+func (r *PostgresPurchaseOrderRepository) Receive(ctx context.Context, id int, items []domain.CheckoutItem) (*domain.PurchaseOrder, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status domain.PurchaseOrderStatus
+	var locationID int
+	err = tx.QueryRowContext(ctx, "SELECT status, location_id FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status, &locationID)
+	if err == sql.ErrNoRows {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != domain.PurchaseOrderOrdered && status != domain.PurchaseOrderPartiallyReceived {
+		return nil, fmt.Errorf("%w: purchase order %d is %s and cannot receive stock", ErrPurchaseOrderStatus, id, status)
+	}
+
+	now := time.Now()
+	for _, item := range mergeItems(items) {
+		var lineID, ordered, received, unitCost int
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, quantity_ordered, quantity_received, unit_cost
+			FROM purchase_order_items
+			WHERE purchase_order_id = $1 AND product_id = $2
+			FOR UPDATE
+		`, id, item.ProductID).Scan(&lineID, &ordered, &received, &unitCost)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: product id %d is not on purchase order %d", ErrInvalidReceipt, item.ProductID, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if received+item.Quantity > ordered {
+			return nil, fmt.Errorf("%w: receiving %d of product id %d exceeds the %d still outstanding",
+				ErrInvalidReceipt, item.Quantity, item.ProductID, ordered-received)
+		}
+
+		if _, err := lockProductStock(ctx, tx, item.ProductID, 0); err != nil {
+			return nil, err
+		}
+		// The cost price becomes the average over the stock on hand and
+		// the received units, so margins follow what stock actually cost
+		_, err = tx.ExecContext(ctx, `
+			UPDATE products
+			SET cost_price = CASE WHEN stock > 0
+				THEN ROUND((stock::numeric * cost_price + $1::numeric * $2) / (stock + $1))
+				ELSE $2 END
+			WHERE id = $3
+		`, item.Quantity, unitCost, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if err := adjustStock(ctx, tx, item.ProductID, locationID, item.Quantity); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE purchase_order_items SET quantity_received = quantity_received + $1 WHERE id = $2", item.Quantity, lineID); err != nil {
+			return nil, err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO stock_receipts (purchase_order_id, product_id, location_id, quantity, unit_cost, received_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, id, item.ProductID, locationID, item.Quantity, unitCost, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var outstanding int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM purchase_order_items WHERE purchase_order_id = $1 AND quantity_received < quantity_ordered", id).Scan(&outstanding)
+	if err != nil {
+		return nil, err
+	}
+	if outstanding == 0 {
+		_, err = tx.ExecContext(ctx, "UPDATE purchase_orders SET status = $1, received_at = $2, updated_at = $2 WHERE id = $3",
+			string(domain.PurchaseOrderReceived), now, id)
+	} else {
+		_, err = tx.ExecContext(ctx, "UPDATE purchase_orders SET status = $1, updated_at = $2 WHERE id = $3",
+			string(domain.PurchaseOrderPartiallyReceived), now, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func lockPurchaseOrder(ctx context.Context, tx *sql.Tx, id int, want domain.PurchaseOrderStatus) error {
+	var status domain.PurchaseOrderStatus
+	err := tx.QueryRowContext(ctx, "SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != want {
+		return fmt.Errorf("%w: purchase order %d is %s, not %s", ErrPurchaseOrderStatus, id, status, want)
+	}
+	return nil
+}
+
+func insertPurchaseOrderItems(ctx context.Context, tx *sql.Tx, purchaseOrderID int, items []domain.PurchaseOrderItem) error {
+	for _, item := range items {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity_ordered, unit_cost)
+			VALUES ($1, $2, $3, $4)
+		`, purchaseOrderID, item.ProductID, item.QuantityOrdered, item.UnitCost)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
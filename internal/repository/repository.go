@@ -1,11 +1,49 @@
 package repository
 
-import "cateogry-api/internal/domain"
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"time"
+)
 
 type CategoryRepository interface {
-	GetAll() ([]domain.Category, error)
-	GetByID(id int) (*domain.Category, error)
-	Create(category domain.Category) (domain.Category, error)
-	Update(id int, category domain.Category) (*domain.Category, error)
-	Delete(id int) error
+	GetAll(ctx context.Context) ([]domain.Category, error)
+	GetByID(ctx context.Context, id int) (*domain.Category, error)
+	Create(ctx context.Context, category domain.Category) (domain.Category, error)
+	Update(ctx context.Context, id int, category domain.Category) (*domain.Category, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type ProductRepository interface {
+	GetAll(ctx context.Context, name string) ([]domain.Product, error)
+	GetByCategory(ctx context.Context, categoryID int) ([]domain.Product, error)
+	GetByID(ctx context.Context, id int) (*domain.Product, error)
+	Create(ctx context.Context, product domain.Product) (domain.Product, error)
+	Update(ctx context.Context, id int, product domain.Product) (*domain.Product, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type TransactionRepository interface {
+	CreateTransaction(ctx context.Context, req domain.CheckoutRequest, price PriceFunc) (*domain.Transaction, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error)
+	GetByID(ctx context.Context, id int) (*domain.Transaction, error)
+	GetDailyReport(ctx context.Context, date time.Time) (domain.DailyReport, error)
+	GetReport(ctx context.Context, startDate, endDate time.Time) (domain.DailyReport, error)
+}
+
+type PromotionRepository interface {
+	GetAll(ctx context.Context) ([]domain.Promotion, error)
+	GetValidAt(ctx context.Context, t time.Time) ([]domain.Promotion, error)
+	GetByID(ctx context.Context, id int) (*domain.Promotion, error)
+	Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error)
+	Update(ctx context.Context, id int, p domain.Promotion) (*domain.Promotion, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type TaxRateRepository interface {
+	GetAll(ctx context.Context) ([]domain.TaxRate, error)
+	GetByID(ctx context.Context, id int) (*domain.TaxRate, error)
+	Create(ctx context.Context, t domain.TaxRate) (domain.TaxRate, error)
+	Update(ctx context.Context, id int, t domain.TaxRate) (*domain.TaxRate, error)
+	Delete(ctx context.Context, id int) error
 }
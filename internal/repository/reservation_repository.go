@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/metrics"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// reservedStockSQL is the quantity of product p held by active, unexpired
+// reservations. Reservation times are always compared with the database clock.
+const reservedStockSQL = `COALESCE((
+			SELECT SUM(ri.quantity)
+			FROM stock_reservation_items ri
+			JOIN stock_reservations sr ON sr.id = ri.reservation_id
+			WHERE ri.product_id = p.id AND sr.status = 'active' AND sr.expires_at > NOW()
+		), 0)`
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationNotActive is wrapped when a checkout names a reservation
+	// that was consumed, released or has expired.
+	ErrReservationNotActive = errors.New("reservation is not active")
+	// ErrNotReserved is wrapped when a checkout asks for products or
+	// quantities its reservation does not hold.
+	ErrNotReserved = errors.New("items are not held by the reservation")
+)
+
+// lockProductStock locks a product row for the rest of tx and returns its
+// stock minus what active reservations other than excludeReservationID hold.
+func lockProductStock(ctx context.Context, tx *sql.Tx, productID, excludeReservationID int) (int, error) {
+	var stock int
+	err := tx.QueryRowContext(ctx, "SELECT stock FROM products WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", productID).Scan(&stock)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("%w: id %d", ErrProductNotFound, productID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return reservedAvailable(ctx, tx, productID, excludeReservationID)
+}
+
+// reservedAvailable returns a product's stock minus what active reservations
+// other than excludeReservationID hold.
+func reservedAvailable(ctx context.Context, tx *sql.Tx, productID, excludeReservationID int) (int, error) {
+	var available int
+	err := tx.QueryRowContext(ctx, `
+		SELECT p.stock - COALESCE((
+			SELECT SUM(ri.quantity)
+			FROM stock_reservation_items ri
+			JOIN stock_reservations sr ON sr.id = ri.reservation_id
+			WHERE ri.product_id = p.id AND sr.status = 'active' AND sr.expires_at > NOW() AND sr.id <> $2
+		), 0)
+		FROM products p
+		WHERE p.id = $1
+	`, productID, excludeReservationID).Scan(&available)
+	return available, err
+}
+
+// lockReservation locks an active reservation created by caller for the rest
+// of tx and returns the quantity it holds per product. Reservations of other
+// callers are reported as not found.
+func lockReservation(ctx context.Context, tx *sql.Tx, id int, caller string) (map[int]int, error) {
+	var active bool
+	var createdBy string
+	err := tx.QueryRowContext(ctx, "SELECT status = $2 AND expires_at > NOW(), created_by FROM stock_reservations WHERE id = $1 FOR UPDATE",
+		id, string(domain.ReservationActive)).Scan(&active, &createdBy)
+	if err == sql.ErrNoRows || (err == nil && createdBy != caller) {
+		return nil, fmt.Errorf("%w: id %d", ErrReservationNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, fmt.Errorf("%w: id %d", ErrReservationNotActive, id)
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT product_id, quantity FROM stock_reservation_items WHERE reservation_id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	held := make(map[int]int)
+	for rows.Next() {
+		var productID, quantity int
+		if err := rows.Scan(&productID, &quantity); err != nil {
+			return nil, err
+		}
+		held[productID] = quantity
+	}
+	return held, rows.Err()
+}
+
+// mergeItems sums quantities per product and sorts by product ID, so product
+// rows are always locked in the same order.
+func mergeItems(items []domain.CheckoutItem) []domain.CheckoutItem {
+	quantities := make(map[int]int, len(items))
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+	merged := make([]domain.CheckoutItem, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, domain.CheckoutItem{ProductID: productID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ProductID < merged[j].ProductID })
+	return merged
+}
+
+type PostgresReservationRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresReservationRepository(db *sql.DB) *PostgresReservationRepository {
+	return &PostgresReservationRepository{db: db}
+}
+
+// Create holds items for ttl on behalf of createdBy. Each product row is
+// locked while its available stock is checked, so concurrent reservations and
+// checkouts cannot oversell.
+func (r *PostgresReservationRepository) Create(ctx context.Context, items []domain.CheckoutItem, ttl time.Duration, createdBy string) (*domain.StockReservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	items = mergeItems(items)
+	for _, item := range items {
+		available, err := lockProductStock(ctx, tx, item.ProductID, 0)
+		if err != nil {
+			return nil, err
+		}
+		if available < item.Quantity {
+			metrics.StockRejected()
+			return nil, fmt.Errorf("%w: stock for product id %d is not enough. available: %d, requested: %d",
+				ErrInsufficientStock, item.ProductID, available, item.Quantity)
+		}
+	}
+
+	res := domain.StockReservation{Items: items, CreatedBy: createdBy}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stock_reservations (status, created_by, expires_at, created_at, updated_at)
+		VALUES ($1, $2, NOW() + $3 * INTERVAL '1 second', NOW(), NOW())
+		RETURNING id, status, expires_at, created_at, updated_at
+	`, string(domain.ReservationActive), createdBy, int(ttl.Seconds())).Scan(&res.ID, &res.Status, &res.ExpiresAt, &res.CreatedAt, &res.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		_, err := tx.ExecContext(ctx, "INSERT INTO stock_reservation_items (reservation_id, product_id, quantity) VALUES ($1, $2, $3)",
+			res.ID, item.ProductID, item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetByID returns a reservation. An active reservation past its expiry is
+// reported as expired even if the expirer has not run yet.
+func (r *PostgresReservationRepository) GetByID(ctx context.Context, id int) (*domain.StockReservation, error) {
+	var res domain.StockReservation
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, CASE WHEN status = $2 AND expires_at <= NOW() THEN $3 ELSE status END,
+		       created_by, transaction_id, expires_at, created_at, updated_at
+		FROM stock_reservations
+		WHERE id = $1
+	`, id, string(domain.ReservationActive), string(domain.ReservationExpired)).Scan(
+		&res.ID, &res.Status, &res.CreatedBy, &res.TransactionID, &res.ExpiresAt, &res.CreatedAt, &res.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT product_id, quantity FROM stock_reservation_items WHERE reservation_id = $1 ORDER BY product_id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res.Items = make([]domain.CheckoutItem, 0)
+	for rows.Next() {
+		var item domain.CheckoutItem
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Release gives the held stock back before the reservation expires. Only the
+// caller that created a reservation can release it.
+func (r *PostgresReservationRepository) Release(ctx context.Context, id int, caller string) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3 AND expires_at > NOW() AND created_by = $4",
+		string(domain.ReservationReleased), id, string(domain.ReservationActive), caller)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrReservationNotFound
+	}
+	return nil
+}
+
+// ExpireStale marks every active reservation past its expiry as expired and
+// returns how many there were.
+func (r *PostgresReservationRepository) ExpireStale(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE stock_reservations SET status = $1, updated_at = NOW() WHERE status = $2 AND expires_at <= NOW()",
+		string(domain.ReservationExpired), string(domain.ReservationActive))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type PostgresSupplierRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSupplierRepository(db *sql.DB) *PostgresSupplierRepository {
+	return &PostgresSupplierRepository{db: db}
+}
+
+func (r *PostgresSupplierRepository) GetAll(ctx context.Context) ([]domain.Supplier, error) {
+	query := "SELECT id, name, contact_name, phone, email, address, created_at, updated_at FROM suppliers WHERE deleted_at IS NULL ORDER BY name"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]domain.Supplier, 0)
+	for rows.Next() {
+		var s domain.Supplier
+		if err := rows.Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+	return suppliers, rows.Err()
+}
+
+func (r *PostgresSupplierRepository) GetByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	query := "SELECT id, name, contact_name, phone, email, address, created_at, updated_at FROM suppliers WHERE id = $1 AND deleted_at IS NULL"
+	var s domain.Supplier
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email, &s.Address, &s.CreatedAt, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("supplier not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *PostgresSupplierRepository) Create(ctx context.Context, s domain.Supplier) (domain.Supplier, error) {
+	query := `
+		INSERT INTO suppliers (name, contact_name, phone, email, address, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, s.Name, s.ContactName, s.Phone, s.Email, s.Address, time.Now()).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return domain.Supplier{}, err
+	}
+	return s, nil
+}
+
+func (r *PostgresSupplierRepository) Update(ctx context.Context, id int, s domain.Supplier) (*domain.Supplier, error) {
+	query := `
+		UPDATE suppliers SET name = $1, contact_name = $2, phone = $3, email = $4, address = $5, updated_at = $6
+		WHERE id = $7 AND deleted_at IS NULL
+		RETURNING id, name, contact_name, phone, email, address, created_at, updated_at
+	`
+	var updated domain.Supplier
+	err := r.db.QueryRowContext(ctx, query, s.Name, s.ContactName, s.Phone, s.Email, s.Address, time.Now(), id).Scan(
+		&updated.ID, &updated.Name, &updated.ContactName, &updated.Phone, &updated.Email, &updated.Address, &updated.CreatedAt, &updated.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("supplier not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *PostgresSupplierRepository) Delete(ctx context.Context, id int) error {
+	query := "UPDATE suppliers SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL"
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("supplier not found")
+	}
+	return nil
+}
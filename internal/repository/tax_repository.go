@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"cateogry-api/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrTaxRateNotFound = errors.New("tax rate not found")
+
+type PostgresTaxRateRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTaxRateRepository(db *sql.DB) *PostgresTaxRateRepository {
+	return &PostgresTaxRateRepository{db: db}
+}
+
+func (r *PostgresTaxRateRepository) GetAll(ctx context.Context) ([]domain.TaxRate, error) {
+	query := "SELECT id, name, category_id, rate, created_at, updated_at FROM tax_rates ORDER BY id"
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]domain.TaxRate, 0)
+	for rows.Next() {
+		var t domain.TaxRate
+		if err := rows.Scan(&t.ID, &t.Name, &t.CategoryID, &t.Rate, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, t)
+	}
+	return rates, rows.Err()
+}
+
+func (r *PostgresTaxRateRepository) GetByID(ctx context.Context, id int) (*domain.TaxRate, error) {
+	query := "SELECT id, name, category_id, rate, created_at, updated_at FROM tax_rates WHERE id = $1"
+	var t domain.TaxRate
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&t.ID, &t.Name, &t.CategoryID, &t.Rate, &t.CreatedAt, &t.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTaxRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *PostgresTaxRateRepository) Create(ctx context.Context, t domain.TaxRate) (domain.TaxRate, error) {
+	query := `
+		INSERT INTO tax_rates (name, category_id, rate, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	now := time.Now()
+	err := r.db.QueryRowContext(ctx, query, t.Name, t.CategoryID, t.Rate, now, now).Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return domain.TaxRate{}, err
+	}
+	return t, nil
+}
+
+func (r *PostgresTaxRateRepository) Update(ctx context.Context, id int, t domain.TaxRate) (*domain.TaxRate, error) {
+	query := `
+		UPDATE tax_rates SET name = $1, category_id = $2, rate = $3, updated_at = $4
+		WHERE id = $5
+		RETURNING id, name, category_id, rate, created_at, updated_at
+	`
+	var updated domain.TaxRate
+	err := r.db.QueryRowContext(ctx, query, t.Name, t.CategoryID, t.Rate, time.Now(), id).Scan(
+		&updated.ID, &updated.Name, &updated.CategoryID, &updated.Rate, &updated.CreatedAt, &updated.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTaxRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *PostgresTaxRateRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tax_rates WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTaxRateNotFound
+	}
+	return nil
+}
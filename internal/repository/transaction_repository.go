@@ -2,72 +2,244 @@ package repository
 
 import (
 	"cateogry-api/internal/domain"
+	"cateogry-api/internal/logging"
+	"cateogry-api/internal/metrics"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 )
 
-type TransactionRepository struct {
+var (
+	// ErrIdempotencyKeyReplayed is returned by CreateTransaction when the
+	// same caller already made the same checkout with its idempotency key.
+	// GetByIdempotencyKey returns that transaction.
+	ErrIdempotencyKeyReplayed = errors.New("idempotency key already used for this checkout")
+	// ErrIdempotencyKeyUsed is returned by CreateTransaction when the
+	// idempotency key was used by another caller or for another checkout.
+	ErrIdempotencyKeyUsed  = errors.New("idempotency key already used")
+	ErrTransactionNotFound = errors.New("transaction not found")
+)
+
+type PostgresTransactionRepository struct {
 	db *sql.DB
 }
 
-func NewTransactionRepository(db *sql.DB) *TransactionRepository {
-	return &TransactionRepository{db: db}
+func NewPostgresTransactionRepository(db *sql.DB) *PostgresTransactionRepository {
+	return &PostgresTransactionRepository{db: db}
 }
 
-func (r *TransactionRepository) CreateTransaction(items []domain.CheckoutItem) (*domain.Transaction, error) {
-	tx, err := r.db.Begin()
+// PriceFunc finalizes the totals of a transaction whose lines have been priced
+// from the products table, before anything is written. Returning an error
+// aborts the checkout.
+type PriceFunc func(t *domain.Transaction) error
+
+// CreateTransaction prices req.Items, one line per product, takes them out
+// of stock at req.LocationID (the default location when 0) and records the
+// sale.
+// Quantities held by active reservations are not sellable, except those held
+// by req.ReservationID, which must belong to req.Caller and hold every item
+// requested. It is consumed by this transaction. When req.CartID is set, the
+// items are the cart's, read once the cart is locked, and the cart is checked
+// out by this transaction.
+func (r *PostgresTransactionRepository) CreateTransaction(ctx context.Context, req domain.CheckoutRequest, price PriceFunc) (*domain.Transaction, error) {
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
-	println("TransactionRepository.CreateTransaction", items)
-	totalAmount := 0
+
+	if req.IdempotencyKey != "" {
+		// Serializes checkouts sharing a key, so a retry sent while the first
+		// attempt is still running waits for it and then finds its row
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", req.IdempotencyKey); err != nil {
+			return nil, err
+		}
+		var caller, hash string
+		err := tx.QueryRowContext(ctx, "SELECT idempotency_caller, idempotency_hash FROM transactions WHERE idempotency_key = $1",
+			req.IdempotencyKey).Scan(&caller, &hash)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return nil, err
+		case caller != req.Caller || hash != req.RequestHash:
+			return nil, ErrIdempotencyKeyUsed
+		default:
+			return nil, ErrIdempotencyKeyReplayed
+		}
+	}
+
+	items := req.Items
+	if req.CartID != 0 {
+		// Locks the cart, so a concurrent checkout of it waits and then fails,
+		// and items cannot be added or changed until the sale commits
+		var open bool
+		err := tx.QueryRowContext(ctx, "SELECT status = $2 AND expires_at > $3 FROM carts WHERE id = $1 FOR UPDATE",
+			req.CartID, string(domain.CartOpen), time.Now()).Scan(&open)
+		if err == sql.ErrNoRows || (err == nil && !open) {
+			return nil, ErrCartNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if items, err = lockedCartItems(ctx, tx.Tx, req.CartID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Merged and sorted like reservations, so checkouts and reservations
+	// lock product rows in the same order and cannot deadlock each other
+	items, reservationID := mergeItems(items), req.ReservationID
+	locationID, err := resolveLocation(ctx, tx, req.LocationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservationID != 0 {
+		held, err := lockReservation(ctx, tx.Tx, reservationID, req.Caller)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if held[item.ProductID] < item.Quantity {
+				return nil, fmt.Errorf("%w: reservation %d holds %d of product id %d, requested: %d",
+					ErrNotReserved, reservationID, held[item.ProductID], item.ProductID, item.Quantity)
+			}
+		}
+	}
+	logging.FromContext(ctx).Debug("Creating transaction", "items", items, "location_id", locationID, "reservation_id", reservationID)
+	subtotal, depleted := 0, 0
 	details := make([]domain.TransactionDetail, 0)
 
 	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+		var productPrice, productCost, categoryID int
+		var productName, categoryName string
 
-		err := tx.QueryRow("SELECT name, price, stock FROM products WHERE id = $1", item.ProductID).Scan(&productName, &productPrice, &stock)
+		available, err := lockProductStock(ctx, tx.Tx, item.ProductID, reservationID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			SELECT p.name, p.price, p.cost_price, p.category_id, COALESCE(c.name, '')
+			FROM products p
+			LEFT JOIN categories c ON p.category_id = c.id
+			WHERE p.id = $1
+		`, item.ProductID).Scan(&productName, &productPrice, &productCost, &categoryID, &categoryName)
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			return nil, fmt.Errorf("%w: id %d", ErrProductNotFound, item.ProductID)
 		}
 		if err != nil {
 			return nil, err
 		}
 
-		if stock < item.Quantity {
-			return nil, fmt.Errorf("stock for product %s is not enough. available: %d, requested: %d", productName, stock, item.Quantity)
+		if available < item.Quantity {
+			metrics.StockRejected()
+			return nil, fmt.Errorf("%w: stock for product %s is not enough. available: %d, requested: %d",
+				ErrInsufficientStock, productName, available, item.Quantity)
 		}
 
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
+		if available == item.Quantity {
+			depleted++
+		}
 
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, item.ProductID)
-		if err != nil {
+		lineSubtotal := productPrice * item.Quantity
+		subtotal += lineSubtotal
+
+		if err := adjustStock(ctx, tx.Tx, item.ProductID, locationID, -item.Quantity); err != nil {
 			return nil, err
 		}
 
 		details = append(details, domain.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName, // Optional, for response
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+			ProductID:    item.ProductID,
+			ProductName:  productName,
+			CategoryID:   categoryID,
+			CategoryName: categoryName,
+			UnitPrice:    productPrice,
+			UnitCost:     productCost,
+			Quantity:     item.Quantity,
+			Subtotal:     lineSubtotal,
 		})
 	}
 
-	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+	t := &domain.Transaction{
+		LocationID:  locationID,
+		Subtotal:    subtotal,
+		TotalAmount: subtotal,
+		Details:     details,
+		Discounts:   make([]domain.AppliedDiscount, 0),
+	}
+	if price != nil {
+		if err := price(t); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO transactions (location_id, subtotal, discount_amount, tax_amount, tax_inclusive, total_amount,
+			idempotency_key, idempotency_caller, idempotency_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+		RETURNING id, created_at
+	`, t.LocationID, t.Subtotal, t.DiscountAmount, t.TaxAmount, t.TaxInclusive, t.TotalAmount,
+		req.IdempotencyKey, req.Caller, req.RequestHash).Scan(&t.ID, &t.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := range details {
-		details[i].TransactionID = transactionID
-		// FIX: Use correctly indexed access as per instruction
-		_, err = tx.Exec("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4)",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal)
+	for i := range t.Details {
+		d := &t.Details[i]
+		d.TransactionID = t.ID
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO transaction_details (transaction_id, product_id, product_name, category_id, category_name,
+				unit_price, unit_cost, quantity, subtotal, discount_amount, tax_rate, tax_amount)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING id
+		`, t.ID, d.ProductID, d.ProductName, d.CategoryID, d.CategoryName,
+			d.UnitPrice, d.UnitCost, d.Quantity, d.Subtotal, d.DiscountAmount, d.TaxRate, d.TaxAmount).Scan(&d.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range t.Discounts {
+		ad := &t.Discounts[i]
+		ad.TransactionID = t.ID
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO transaction_discounts (transaction_id, promotion_id, code, name, type, product_id, amount)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7)
+			RETURNING id
+		`, t.ID, ad.PromotionID, ad.Code, ad.Name, string(ad.Type), ad.ProductID, ad.Amount).Scan(&ad.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if reservationID != 0 {
+		_, err = tx.ExecContext(ctx, "UPDATE stock_reservations SET status = $1, transaction_id = $2, updated_at = NOW() WHERE id = $3",
+			string(domain.ReservationConsumed), t.ID, reservationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CartID != 0 {
+		_, err = tx.ExecContext(ctx, "UPDATE carts SET status = $1, transaction_id = $2, updated_at = $3 WHERE id = $4",
+			string(domain.CartCheckedOut), t.ID, time.Now(), req.CartID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range t.Payments {
+		p := &t.Payments[i]
+		p.TransactionID = t.ID
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO transaction_payments (transaction_id, method, amount, tendered, change_amount, reference)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, t.ID, string(p.Method), p.Amount, p.Tendered, p.Change, p.Reference).Scan(&p.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -76,50 +248,157 @@ func (r *TransactionRepository) CreateTransaction(items []domain.CheckoutItem) (
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
+	metrics.StockDepleted(depleted)
 
-	return &domain.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		CreatedAt:   time.Now(), // This is approximate, actual time is in DB
-		Details:     details,
-	}, nil
+	return t, nil
+}
+
+// GetByIdempotencyKey returns the transaction created with key.
+func (r *PostgresTransactionRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM transactions WHERE idempotency_key = $1", key).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
 }
 
-func (r *TransactionRepository) GetDailyReport(date time.Time) (domain.DailyReport, error) {
+func (r *PostgresTransactionRepository) GetByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	var t domain.Transaction
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, COALESCE(location_id, 0), subtotal, discount_amount, tax_amount, tax_inclusive, total_amount, created_at
+		FROM transactions WHERE id = $1
+	`, id).Scan(&t.ID, &t.LocationID, &t.Subtotal, &t.DiscountAmount, &t.TaxAmount, &t.TaxInclusive, &t.TotalAmount, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Details are read from the snapshot columns only, never joined to products,
+	// so the response matches what was charged at checkout.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, transaction_id, product_id, product_name, category_id, category_name,
+		       unit_price, unit_cost, quantity, subtotal, discount_amount, tax_rate, tax_amount
+		FROM transaction_details
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t.Details = make([]domain.TransactionDetail, 0)
+	for rows.Next() {
+		var d domain.TransactionDetail
+		var productID, categoryID sql.NullInt64
+		if err := rows.Scan(&d.ID, &d.TransactionID, &productID, &d.ProductName, &categoryID, &d.CategoryName, &d.UnitPrice, &d.UnitCost, &d.Quantity, &d.Subtotal, &d.DiscountAmount, &d.TaxRate, &d.TaxAmount); err != nil {
+			return nil, err
+		}
+		d.ProductID = int(productID.Int64)
+		d.CategoryID = int(categoryID.Int64)
+		t.Details = append(t.Details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	t.Discounts, err = r.getDiscounts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t.Payments, err = r.getPayments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *PostgresTransactionRepository) getPayments(ctx context.Context, transactionID int) ([]domain.Payment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, transaction_id, method, amount, tendered, change_amount, reference
+		FROM transaction_payments
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make([]domain.Payment, 0)
+	for rows.Next() {
+		var p domain.Payment
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.Tendered, &p.Change, &p.Reference); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) getDiscounts(ctx context.Context, transactionID int) ([]domain.AppliedDiscount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, transaction_id, COALESCE(promotion_id, 0), code, name, type, COALESCE(product_id, 0), amount
+		FROM transaction_discounts
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discounts := make([]domain.AppliedDiscount, 0)
+	for rows.Next() {
+		var ad domain.AppliedDiscount
+		if err := rows.Scan(&ad.ID, &ad.TransactionID, &ad.PromotionID, &ad.Code, &ad.Name, &ad.Type, &ad.ProductID, &ad.Amount); err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, ad)
+	}
+	return discounts, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) GetDailyReport(ctx context.Context, date time.Time) (domain.DailyReport, error) {
 	// Start of the day
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	// End of the day
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
-	return r.GetReport(startOfDay, endOfDay)
+	return r.GetReport(ctx, startOfDay, endOfDay)
 }
 
-func (r *TransactionRepository) GetReport(startDate, endDate time.Time) (domain.DailyReport, error) {
+func (r *PostgresTransactionRepository) GetReport(ctx context.Context, startDate, endDate time.Time) (domain.DailyReport, error) {
 	var report domain.DailyReport
 
 	// 1. Total Revenue and Total Transactions
 	queryRevenue := `
-		SELECT COALESCE(SUM(total_amount), 0), COUNT(id)
+		SELECT COALESCE(SUM(total_amount), 0), COUNT(id), COALESCE(SUM(discount_amount), 0), COALESCE(SUM(tax_amount), 0)
 		FROM transactions
 		WHERE created_at >= $1 AND created_at < $2
 	`
-	err := r.db.QueryRow(queryRevenue, startDate, endDate).Scan(&report.TotalRevenue, &report.TotalTransactions)
+	err := r.db.QueryRowContext(ctx, queryRevenue, startDate, endDate).Scan(&report.TotalRevenue, &report.TotalTransactions, &report.TotalDiscount, &report.TotalTax)
 	if err != nil {
 		return report, err
 	}
 
 	// 2. Best Selling Product
 	queryBestSeller := `
-		SELECT p.name, COALESCE(SUM(td.quantity), 0) as qty_sold
+		SELECT td.product_name, COALESCE(SUM(td.quantity), 0) as qty_sold
 		FROM transaction_details td
 		JOIN transactions t ON td.transaction_id = t.id
-		JOIN products p ON td.product_id = p.id
 		WHERE t.created_at >= $1 AND t.created_at < $2
-		GROUP BY p.name
+		GROUP BY td.product_name
 		ORDER BY qty_sold DESC
 		LIMIT 1
 	`
-	err = r.db.QueryRow(queryBestSeller, startDate, endDate).Scan(&report.BestSellingProduct.Name, &report.BestSellingProduct.QtySold)
+	err = r.db.QueryRowContext(ctx, queryBestSeller, startDate, endDate).Scan(&report.BestSellingProduct.Name, &report.BestSellingProduct.QtySold)
 	if err == sql.ErrNoRows {
 		// No transactions, so no best seller
 		report.BestSellingProduct = domain.BestSellingProduct{Name: "-", QtySold: 0}
@@ -127,5 +406,165 @@ func (r *TransactionRepository) GetReport(startDate, endDate time.Time) (domain.
 		return report, err
 	}
 
+	// 3. Discounts per promotion
+	queryPromotions := `
+		SELECT COALESCE(td.promotion_id, 0), td.name, td.code, COUNT(DISTINCT td.transaction_id), SUM(td.amount) as total_discount
+		FROM transaction_discounts td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY td.promotion_id, td.name, td.code
+		ORDER BY total_discount DESC
+	`
+	rows, err := r.db.QueryContext(ctx, queryPromotions, startDate, endDate)
+	if err != nil {
+		return report, err
+	}
+	defer rows.Close()
+
+	report.Promotions = make([]domain.PromotionUsage, 0)
+	for rows.Next() {
+		var u domain.PromotionUsage
+		if err := rows.Scan(&u.PromotionID, &u.Name, &u.Code, &u.TimesApplied, &u.TotalDiscount); err != nil {
+			return report, err
+		}
+		report.Promotions = append(report.Promotions, u)
+	}
+	if err := rows.Err(); err != nil {
+		return report, err
+	}
+
+	// 4. Tax per rate. The taxable amount excludes tax for inclusive-priced sales.
+	queryTaxes := `
+		SELECT td.tax_rate,
+		       SUM(td.subtotal - td.discount_amount - CASE WHEN t.tax_inclusive THEN td.tax_amount ELSE 0 END),
+		       SUM(td.tax_amount)
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY td.tax_rate
+		ORDER BY td.tax_rate
+	`
+	taxRows, err := r.db.QueryContext(ctx, queryTaxes, startDate, endDate)
+	if err != nil {
+		return report, err
+	}
+	defer taxRows.Close()
+
+	report.Taxes = make([]domain.TaxSummary, 0)
+	for taxRows.Next() {
+		var ts domain.TaxSummary
+		if err := taxRows.Scan(&ts.Rate, &ts.TaxableAmount, &ts.TaxAmount); err != nil {
+			return report, err
+		}
+		report.Taxes = append(report.Taxes, ts)
+	}
+	if err := taxRows.Err(); err != nil {
+		return report, err
+	}
+
+	// 5. Revenue per payment method
+	queryPayments := `
+		SELECT tp.method, COUNT(DISTINCT tp.transaction_id), SUM(tp.amount) as amount
+		FROM transaction_payments tp
+		JOIN transactions t ON tp.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY tp.method
+		ORDER BY amount DESC
+	`
+	paymentRows, err := r.db.QueryContext(ctx, queryPayments, startDate, endDate)
+	if err != nil {
+		return report, err
+	}
+	defer paymentRows.Close()
+
+	report.PaymentMethods = make([]domain.PaymentMethodSummary, 0)
+	for paymentRows.Next() {
+		var pm domain.PaymentMethodSummary
+		if err := paymentRows.Scan(&pm.Method, &pm.Transactions, &pm.Amount); err != nil {
+			return report, err
+		}
+		report.PaymentMethods = append(report.PaymentMethods, pm)
+	}
+	if err := paymentRows.Err(); err != nil {
+		return report, err
+	}
+
+	// 6. Gross margin per product. Net sales exclude discounts and tax; cost is
+	// the cost price snapshot on each line, so sales recorded before cost prices
+	// were tracked count as zero cost.
+	queryProductMargins := `
+		SELECT COALESCE(td.product_id, 0), td.product_name, SUM(td.quantity),
+		       SUM(td.subtotal - td.discount_amount - CASE WHEN t.tax_inclusive THEN td.tax_amount ELSE 0 END),
+		       SUM(td.unit_cost * td.quantity)
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY td.product_id, td.product_name
+		ORDER BY td.product_name
+	`
+	productRows, err := r.db.QueryContext(ctx, queryProductMargins, startDate, endDate)
+	if err != nil {
+		return report, err
+	}
+	defer productRows.Close()
+
+	report.ProductMargins = make([]domain.ProductMargin, 0)
+	for productRows.Next() {
+		var pm domain.ProductMargin
+		if err := productRows.Scan(&pm.ProductID, &pm.Name, &pm.QtySold, &pm.NetSales, &pm.COGS); err != nil {
+			return report, err
+		}
+		pm.GrossProfit = pm.NetSales - pm.COGS
+		pm.MarginPercent = marginPercent(pm.GrossProfit, pm.NetSales)
+		report.NetSales += pm.NetSales
+		report.TotalCOGS += pm.COGS
+		report.ProductMargins = append(report.ProductMargins, pm)
+	}
+	if err := productRows.Err(); err != nil {
+		return report, err
+	}
+	report.GrossProfit = report.NetSales - report.TotalCOGS
+	report.MarginPercent = marginPercent(report.GrossProfit, report.NetSales)
+
+	// 7. Gross margin per category
+	queryCategoryMargins := `
+		SELECT COALESCE(td.category_id, 0), td.category_name, SUM(td.quantity),
+		       SUM(td.subtotal - td.discount_amount - CASE WHEN t.tax_inclusive THEN td.tax_amount ELSE 0 END),
+		       SUM(td.unit_cost * td.quantity)
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE t.created_at >= $1 AND t.created_at < $2
+		GROUP BY td.category_id, td.category_name
+		ORDER BY td.category_name
+	`
+	categoryRows, err := r.db.QueryContext(ctx, queryCategoryMargins, startDate, endDate)
+	if err != nil {
+		return report, err
+	}
+	defer categoryRows.Close()
+
+	report.CategoryMargins = make([]domain.CategoryMargin, 0)
+	for categoryRows.Next() {
+		var cm domain.CategoryMargin
+		if err := categoryRows.Scan(&cm.CategoryID, &cm.Name, &cm.QtySold, &cm.NetSales, &cm.COGS); err != nil {
+			return report, err
+		}
+		cm.GrossProfit = cm.NetSales - cm.COGS
+		cm.MarginPercent = marginPercent(cm.GrossProfit, cm.NetSales)
+		report.CategoryMargins = append(report.CategoryMargins, cm)
+	}
+	if err := categoryRows.Err(); err != nil {
+		return report, err
+	}
+
 	return report, nil
 }
+
+// marginPercent returns profit as a percentage of sales, rounded to two
+// decimals, or 0 when there were no sales.
+func marginPercent(profit, sales int) float64 {
+	if sales == 0 {
+		return 0
+	}
+	return math.Round(float64(profit)*10000/float64(sales)) / 100
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txKey struct{}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// runInTx runs fn in one transaction on db. Repository calls made with the
+// ctx passed to fn take part in it, and it commits only if fn returns nil.
+// Inside another runInTx, fn simply joins the outer transaction.
+func runInTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// conn returns the transaction runInTx put in ctx, or db outside of one.
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// forUpdateInTx returns clause inside a runInTx transaction and "" outside.
+// Reads that snapshot a row before it is changed use it to hold the row
+// until the change commits.
+func forUpdateInTx(ctx context.Context, clause string) string {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return " " + clause
+	}
+	return ""
+}
+
+// ownedTx is a transaction a repository method runs its statements in. It
+// is the one runInTx put in ctx, if any, in which case Commit and Rollback
+// leave ending it to runInTx.
+type ownedTx struct {
+	*sql.Tx
+	owned bool
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (*ownedTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &ownedTx{Tx: tx}, nil
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ownedTx{Tx: tx, owned: true}, nil
+}
+
+func (t *ownedTx) Commit() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *ownedTx) Rollback() error {
+	if !t.owned {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,52 @@
+// Package response has the ResponseWriter wrapper the middlewares use to see
+// what a handler wrote.
+package response
+
+import "net/http"
+
+// Recorder remembers the status code and body size a handler wrote. The
+// status is the first one written; a body without WriteHeader means 200.
+type Recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w}
+}
+
+func (r *Recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *Recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Status returns the status written so far, 200 when the handler wrote
+// nothing.
+func (r *Recorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// Bytes returns the size of the body written so far.
+func (r *Recorder) Bytes() int {
+	return r.bytes
+}
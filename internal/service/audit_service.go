@@ -0,0 +1,125 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const defaultAuditLimit = 100
+
+// ErrInvalidAuditFilter is wrapped by GET /audit filter validation errors.
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+type AuditService struct {
+	repo *repository.PostgresAuditRepository
+}
+
+func NewAuditService(repo *repository.PostgresAuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Transaction runs fn in one database transaction, so that the snapshot
+// taken before a change, the change and its audit entry commit together or
+// not at all. Repositories take part when called with the ctx passed to fn.
+// A nil AuditService runs fn without a transaction.
+func (s *AuditService) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s == nil {
+		return fn(ctx)
+	}
+	return s.repo.RunInTx(ctx, fn)
+}
+
+// Record logs a change made by the caller in ctx. before is nil for creates
+// and after is nil for deletes. Call it inside Transaction with the ctx of the
+// change, so that a failure to record undoes the change. A nil AuditService
+// records nothing.
+func (s *AuditService) Record(ctx context.Context, action domain.AuditAction, entityType string, entityID int, before, after any) error {
+	if s == nil {
+		return nil
+	}
+
+	entry := domain.AuditEntry{
+		Actor:      callerSubject(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		RequestID:  RequestIDFromContext(ctx),
+	}
+	var err error
+	if entry.Before, err = marshalSnapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = marshalSnapshot(after); err != nil {
+		return err
+	}
+	if entry.Changes, err = diffSnapshots(entry.Before, entry.After); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+func (s *AuditService) Find(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	switch f.Action {
+	case "", domain.AuditCreate, domain.AuditUpdate, domain.AuditDelete:
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidAuditFilter, f.Action)
+	}
+	if f.Limit <= 0 || f.Limit > 1000 {
+		f.Limit = defaultAuditLimit
+	}
+	return s.repo.Find(ctx, f)
+}
+
+func marshalSnapshot(v any) (json.RawMessage, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// diffSnapshots compares the top-level fields of two JSON objects. A field
+// missing on one side is reported with a null value on that side.
+func diffSnapshots(before, after json.RawMessage) (map[string]domain.FieldChange, error) {
+	var from, to map[string]any
+	if len(before) > 0 {
+		if err := json.Unmarshal(before, &from); err != nil {
+			return nil, err
+		}
+	}
+	if len(after) > 0 {
+		if err := json.Unmarshal(after, &to); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make(map[string]domain.FieldChange)
+	for k, v := range from {
+		if w, ok := to[k]; !ok || !reflect.DeepEqual(v, w) {
+			changes[k] = domain.FieldChange{From: v, To: to[k]}
+		}
+	}
+	for k, w := range to {
+		if _, ok := from[k]; !ok {
+			changes[k] = domain.FieldChange{To: w}
+		}
+	}
+	return changes, nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the current request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the ID stored by WithRequestID, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const apiKeyPrefix = "ck_"
+
+var (
+	// ErrUnauthenticated is wrapped by every rejected credential.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrInvalidAPIKey is wrapped by API key validation errors.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAPIKeyNotFound is returned for unknown or already revoked keys.
+	ErrAPIKeyNotFound = repository.ErrAPIKeyNotFound
+)
+
+// AuthSettings configures which credentials are accepted. A JWT signing
+// method is only accepted when its key is set, and the caller's role is read
+// from the token's "role" claim. BootstrapAPIKey, when set, is accepted as an
+// admin key so the first keys can be created.
+type AuthSettings struct {
+	JWTSecret       []byte
+	JWTPublicKey    *rsa.PublicKey
+	JWTIssuer       string
+	JWTAudience     string
+	BootstrapAPIKey string
+}
+
+type AuthService struct {
+	repo     *repository.PostgresAPIKeyRepository
+	authz    *Authorizer
+	settings AuthSettings
+}
+
+func NewAuthService(repo *repository.PostgresAPIKeyRepository, authz *Authorizer, settings AuthSettings) *AuthService {
+	return &AuthService{repo: repo, authz: authz, settings: settings}
+}
+
+// ParseRSAPublicKey reads a PEM encoded RSA public key for RS256 tokens.
+func ParseRSAPublicKey(pem []byte) (*rsa.PublicKey, error) {
+	return jwt.ParseRSAPublicKeyFromPEM(pem)
+}
+
+func (s *AuthService) GetAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// CreateAPIKey generates a new key acting as role, or as a viewer when role
+// is empty. The returned key carries the plaintext in Key; it cannot be
+// retrieved again.
+func (s *AuthService) CreateAPIKey(ctx context.Context, name string, role domain.Role) (domain.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.APIKey{}, fmt.Errorf("%w: name is required", ErrInvalidAPIKey)
+	}
+	if role == "" {
+		role = domain.RoleViewer
+	}
+	if !s.authz.HasRole(role) {
+		return domain.APIKey{}, fmt.Errorf("%w: unknown role %q", ErrInvalidAPIKey, role)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.APIKey{}, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	created, err := s.repo.Create(ctx, domain.APIKey{Name: name, Role: role, Prefix: key[:len(apiKeyPrefix)+8]}, hashAPIKey(key))
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	created.Key = key
+	return created, nil
+}
+
+func (s *AuthService) RevokeAPIKey(ctx context.Context, id int) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// AuthenticateAPIKey resolves the caller behind an API key.
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (*domain.Principal, error) {
+	hash := hashAPIKey(key)
+	if s.settings.BootstrapAPIKey != "" &&
+		subtle.ConstantTimeCompare([]byte(hash), []byte(hashAPIKey(s.settings.BootstrapAPIKey))) == 1 {
+		return &domain.Principal{Subject: "api_key:bootstrap", Method: "api_key", Role: domain.RoleAdmin}, nil
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, fmt.Errorf("%w: malformed api key", ErrUnauthenticated)
+	}
+
+	k, err := s.repo.Touch(ctx, hash)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, fmt.Errorf("%w: unknown or revoked api key", ErrUnauthenticated)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Key names are labels, not identities: two keys may share one, or match a
+	// token's subject
+	return &domain.Principal{Subject: "api_key:" + strconv.Itoa(k.ID), Method: "api_key", Role: k.Role, APIKeyID: k.ID}, nil
+}
+
+// AuthenticateJWT verifies an HS256 or RS256 bearer token. Tokens must carry
+// an expiry, and the issuer and audience when those are configured.
+func (s *AuthService) AuthenticateJWT(token string) (*domain.Principal, error) {
+	var methods []string
+	if len(s.settings.JWTSecret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if s.settings.JWTPublicKey != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrUnauthenticated)
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if s.settings.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.settings.JWTIssuer))
+	}
+	if s.settings.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(s.settings.JWTAudience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if t.Method == jwt.SigningMethodRS256 {
+			return s.settings.JWTPublicKey, nil
+		}
+		return s.settings.JWTSecret, nil
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+	role, _ := claims["role"].(string)
+	return &domain.Principal{Subject: "jwt:" + subject, Method: "jwt", Role: domain.Role(role)}, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are 256 random bits, so a
+// fast unsalted hash is enough to keep them unusable if the table leaks.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p *domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the caller stored by WithPrincipal, or nil.
+func PrincipalFromContext(ctx context.Context) *domain.Principal {
+	p, _ := ctx.Value(principalKey{}).(*domain.Principal)
+	return p
+}
+
+// callerSubject returns the subject of the caller in ctx, or "anonymous" when
+// there is none.
+func callerSubject(ctx context.Context) string {
+	if p := PrincipalFromContext(ctx); p != nil {
+		return p.Subject
+	}
+	return "anonymous"
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"cateogry-api/internal/testdb"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testJWTSecret = []byte("test-secret")
+
+func signTestJWT(t *testing.T, subject string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": subject, "role": "cashier", "exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString(testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthSubjectsDoNotCollide(t *testing.T) {
+	s := NewAuthService(nil, NewAuthorizer(DefaultRolePermissions()), AuthSettings{
+		JWTSecret: testJWTSecret, BootstrapAPIKey: "bootstrap-key",
+	})
+
+	bootstrap, err := s.AuthenticateAPIKey(context.Background(), "bootstrap-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := s.AuthenticateJWT(signTestJWT(t, "bootstrap"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Subject != "jwt:bootstrap" {
+		t.Errorf("JWT subject = %q, want jwt:bootstrap", user.Subject)
+	}
+	if bootstrap.Subject == user.Subject {
+		t.Errorf("bootstrap key and JWT user \"bootstrap\" share subject %q", user.Subject)
+	}
+}
+
+func TestAuthAPIKeySubject(t *testing.T) {
+	db := testdb.Open(t)
+	ctx := context.Background()
+	s := NewAuthService(repository.NewPostgresAPIKeyRepository(db), NewAuthorizer(DefaultRolePermissions()), AuthSettings{JWTSecret: testJWTSecret})
+
+	// Two keys and a JWT user all named alice are three callers
+	subjects := make(map[string]bool)
+	for range 2 {
+		created, err := s.CreateAPIKey(ctx, "alice", domain.RoleCashier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := s.AuthenticateAPIKey(ctx, created.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subjects[key.Subject] = true
+	}
+	user, err := s.AuthenticateJWT(signTestJWT(t, "alice"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subjects[user.Subject] = true
+	if len(subjects) != 3 {
+		t.Errorf("subjects = %v, want 3 distinct", subjects)
+	}
+}
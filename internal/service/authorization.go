@@ -0,0 +1,101 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// AllPermissions grants every permission, including ones added later.
+const AllPermissions domain.Permission = "*"
+
+// RolePermissions maps each role to the permissions it grants.
+type RolePermissions map[domain.Role][]domain.Permission
+
+// DefaultRolePermissions lets admins do everything, cashiers sell and look
+// up what they sell, and viewers read everything except API keys.
+func DefaultRolePermissions() RolePermissions {
+	return RolePermissions{
+		domain.RoleAdmin: {AllPermissions},
+		domain.RoleCashier: {
+			domain.PermCategoriesRead,
+			domain.PermProductsRead,
+			domain.PermLocationsRead,
+			domain.PermPromotionsRead,
+			domain.PermTaxesRead,
+			domain.PermCheckoutCreate,
+			domain.PermTransactionsRead,
+		},
+		domain.RoleViewer: {
+			domain.PermCategoriesRead,
+			domain.PermProductsRead,
+			domain.PermLocationsRead,
+			domain.PermSuppliersRead,
+			domain.PermPurchaseOrdersRead,
+			domain.PermPromotionsRead,
+			domain.PermTaxesRead,
+			domain.PermTransactionsRead,
+			domain.PermReportsRead,
+		},
+	}
+}
+
+// ParseRolePermissions reads overrides in the form
+// "cashier=checkout:create,products:read;auditor=reports:read" and applies
+// them on top of the defaults. A role listed here replaces its default
+// permissions entirely; "*" grants all of them.
+func ParseRolePermissions(s string) (RolePermissions, error) {
+	roles := DefaultRolePermissions()
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		role, list, ok := strings.Cut(entry, "=")
+		role = strings.TrimSpace(role)
+		if !ok || role == "" {
+			return nil, fmt.Errorf("expected role=permission,... but got %q", entry)
+		}
+
+		perms := make([]domain.Permission, 0)
+		for _, perm := range strings.Split(list, ",") {
+			if perm = strings.TrimSpace(perm); perm != "" {
+				perms = append(perms, domain.Permission(perm))
+			}
+		}
+		roles[domain.Role(role)] = perms
+	}
+	return roles, nil
+}
+
+// Authorizer decides whether a caller's role grants a permission.
+type Authorizer struct {
+	grants map[domain.Role]map[domain.Permission]bool
+}
+
+func NewAuthorizer(roles RolePermissions) *Authorizer {
+	grants := make(map[domain.Role]map[domain.Permission]bool, len(roles))
+	for role, perms := range roles {
+		grants[role] = make(map[domain.Permission]bool, len(perms))
+		for _, perm := range perms {
+			grants[role][perm] = true
+		}
+	}
+	return &Authorizer{grants: grants}
+}
+
+// HasRole reports whether role is configured, even if it grants nothing.
+func (a *Authorizer) HasRole(role domain.Role) bool {
+	_, ok := a.grants[role]
+	return ok
+}
+
+// Allowed reports whether p may use routes guarded by perm. A nil principal
+// is never allowed.
+func (a *Authorizer) Allowed(p *domain.Principal, perm domain.Permission) bool {
+	if p == nil {
+		return false
+	}
+	grants := a.grants[p.Role]
+	return grants[AllPermissions] || grants[perm]
+}
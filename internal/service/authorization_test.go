@@ -0,0 +1,70 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"testing"
+)
+
+func TestDefaultRolePermissions(t *testing.T) {
+	authz := NewAuthorizer(DefaultRolePermissions())
+	tests := []struct {
+		role domain.Role
+		perm domain.Permission
+		want bool
+	}{
+		{domain.RoleAdmin, domain.PermCategoriesWrite, true},
+		{domain.RoleAdmin, domain.PermAPIKeysManage, true},
+		{domain.RoleCashier, domain.PermCheckoutCreate, true},
+		{domain.RoleCashier, domain.PermProductsRead, true},
+		{domain.RoleCashier, domain.PermCategoriesWrite, false},
+		{domain.RoleCashier, domain.PermReportsRead, false},
+		{domain.RoleViewer, domain.PermReportsRead, true},
+		{domain.RoleViewer, domain.PermCheckoutCreate, false},
+		{domain.RoleViewer, domain.PermAPIKeysManage, false},
+		{"unknown", domain.PermCategoriesRead, false},
+	}
+	for _, tt := range tests {
+		if got := authz.Allowed(&domain.Principal{Role: tt.role}, tt.perm); got != tt.want {
+			t.Errorf("Allowed(%s, %s) = %v, want %v", tt.role, tt.perm, got, tt.want)
+		}
+	}
+	if authz.Allowed(nil, domain.PermCategoriesRead) {
+		t.Error("nil principal was allowed")
+	}
+}
+
+func TestParseRolePermissions(t *testing.T) {
+	roles, err := ParseRolePermissions(" cashier = checkout:create ; auditor=reports:read,transactions:read;root=*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	authz := NewAuthorizer(roles)
+
+	cashier := &domain.Principal{Role: domain.RoleCashier}
+	if !authz.Allowed(cashier, domain.PermCheckoutCreate) {
+		t.Error("cashier lost checkout:create")
+	}
+	if authz.Allowed(cashier, domain.PermProductsRead) {
+		t.Error("cashier override did not replace the default permissions")
+	}
+	if !authz.Allowed(&domain.Principal{Role: "auditor"}, domain.PermTransactionsRead) {
+		t.Error("auditor role was not added")
+	}
+	if !authz.Allowed(&domain.Principal{Role: "root"}, domain.PermAPIKeysManage) {
+		t.Error("wildcard did not grant everything")
+	}
+	if !authz.Allowed(&domain.Principal{Role: domain.RoleViewer}, domain.PermReportsRead) {
+		t.Error("viewer defaults were dropped")
+	}
+	if !authz.HasRole("auditor") || authz.HasRole("nobody") {
+		t.Error("HasRole does not match the configured roles")
+	}
+}
+
+func TestParseRolePermissionsInvalid(t *testing.T) {
+	for _, s := range []string{"cashier", "=reports:read"} {
+		if _, err := ParseRolePermissions(s); err == nil {
+			t.Errorf("ParseRolePermissions(%q) succeeded", s)
+		}
+	}
+}
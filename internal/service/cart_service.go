@@ -0,0 +1,104 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"cateogry-api/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidCart is wrapped by cart validation errors.
+	ErrInvalidCart = errors.New("invalid cart")
+	// ErrCartNotFound is returned for missing, expired or already checked out carts.
+	ErrCartNotFound = repository.ErrCartNotFound
+)
+
+type CartService struct {
+	repo           *repository.PostgresCartRepository
+	productRepo    repository.ProductRepository
+	transactionSvc *TransactionService
+	ttl            time.Duration
+}
+
+// NewCartService creates a CartService whose carts expire ttl after they were
+// last modified.
+func NewCartService(repo *repository.PostgresCartRepository, productRepo repository.ProductRepository, transactionSvc *TransactionService, ttl time.Duration) *CartService {
+	return &CartService{repo: repo, productRepo: productRepo, transactionSvc: transactionSvc, ttl: ttl}
+}
+
+func (s *CartService) Create(ctx context.Context) (*domain.Cart, error) {
+	return s.repo.Create(ctx, time.Now().Add(s.ttl))
+}
+
+func (s *CartService) GetByID(ctx context.Context, id int) (*domain.Cart, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *CartService) AddItem(ctx context.Context, cartID int, item domain.CheckoutItem) (*domain.Cart, error) {
+	if item.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidCart)
+	}
+	if _, err := s.productRepo.GetByID(ctx, item.ProductID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddItem(ctx, cartID, item.ProductID, item.Quantity, time.Now().Add(s.ttl)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, cartID)
+}
+
+// UpdateItem sets the quantity of a cart line; a quantity of 0 removes it.
+func (s *CartService) UpdateItem(ctx context.Context, cartID, productID, quantity int) (*domain.Cart, error) {
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity must not be negative", ErrInvalidCart)
+	}
+	if quantity == 0 {
+		return s.RemoveItem(ctx, cartID, productID)
+	}
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetItem(ctx, cartID, productID, quantity, time.Now().Add(s.ttl)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, cartID, productID int) (*domain.Cart, error) {
+	if err := s.repo.RemoveItem(ctx, cartID, productID, time.Now().Add(s.ttl)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, cartID)
+}
+
+func (s *CartService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Checkout commits the cart through the regular checkout path. The cart's
+// items are read and marked checked out in the same database transaction as
+// the sale, so a failed checkout leaves it open, concurrent checkouts sell it
+// only once and nothing added meanwhile goes unpaid.
+func (s *CartService) Checkout(ctx context.Context, id int, req domain.CartCheckoutRequest) (_ *domain.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "CartService.Checkout")
+	defer func() { tracing.End(span, err) }()
+
+	t, err := s.transactionSvc.Checkout(ctx, domain.CheckoutRequest{
+		LocationID: req.LocationID,
+		PromoCodes: req.PromoCodes,
+		Payments:   req.Payments,
+		CartID:     id,
+	})
+	if errors.Is(err, repository.ErrCartEmpty) {
+		return nil, fmt.Errorf("%w: cart is empty", ErrInvalidCart)
+	}
+	return t, err
+}
+
+func (s *CartService) CleanUpExpired(ctx context.Context) error {
+	return s.repo.CleanUpExpired(ctx)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidLocation is wrapped by location and stock transfer validation errors.
+	ErrInvalidLocation = errors.New("invalid location")
+	// ErrLocationNotFound is returned for unknown or deleted locations.
+	ErrLocationNotFound = repository.ErrLocationNotFound
+)
+
+type LocationService struct {
+	repo *repository.PostgresLocationRepository
+}
+
+func NewLocationService(repo *repository.PostgresLocationRepository) *LocationService {
+	return &LocationService{repo: repo}
+}
+
+func (s *LocationService) GetAll(ctx context.Context) ([]domain.Location, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *LocationService) GetByID(ctx context.Context, id int) (*domain.Location, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *LocationService) Create(ctx context.Context, l domain.Location) (domain.Location, error) {
+	if err := validateLocation(&l); err != nil {
+		return domain.Location{}, err
+	}
+	return s.repo.Create(ctx, l)
+}
+
+func (s *LocationService) Update(ctx context.Context, id int, l domain.Location) (*domain.Location, error) {
+	if err := validateLocation(&l); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, l)
+}
+
+func (s *LocationService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *LocationService) Transfer(ctx context.Context, t domain.StockTransfer) (domain.StockTransfer, error) {
+	if t.Quantity <= 0 {
+		return domain.StockTransfer{}, fmt.Errorf("%w: quantity must be positive", ErrInvalidLocation)
+	}
+	if t.FromLocationID == t.ToLocationID {
+		return domain.StockTransfer{}, fmt.Errorf("%w: from_location_id and to_location_id must differ", ErrInvalidLocation)
+	}
+	return s.repo.Transfer(ctx, t)
+}
+
+func (s *LocationService) GetTransfers(ctx context.Context, productID int) ([]domain.StockTransfer, error) {
+	return s.repo.GetTransfers(ctx, productID)
+}
+
+// validateLocation checks l and fills in the default type.
+func validateLocation(l *domain.Location) error {
+	if strings.TrimSpace(l.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLocation)
+	}
+	switch l.Type {
+	case "":
+		l.Type = domain.LocationStore
+	case domain.LocationStore, domain.LocationWarehouse:
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidLocation, l.Type)
+	}
+	return nil
+}
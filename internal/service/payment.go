@@ -0,0 +1,76 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPayment is wrapped by every payment validation error at checkout.
+var ErrInvalidPayment = errors.New("invalid payment")
+
+// applyPayments settles t.TotalAmount with the given payments. Non-cash
+// payments are applied first and must not exceed what is still owed; cash
+// covers the rest and any excess is returned as change. Together the payments
+// must cover the total, and a cash payment must not be given once the total
+// is covered. No payments means the total was paid in exact cash.
+func applyPayments(t *domain.Transaction, payments []domain.CheckoutPayment) error {
+	t.Payments = make([]domain.Payment, 0, max(len(payments), 1))
+	if len(payments) == 0 {
+		if t.TotalAmount > 0 {
+			t.Payments = append(t.Payments, domain.Payment{Method: domain.PaymentCash, Amount: t.TotalAmount, Tendered: t.TotalAmount})
+		}
+		return nil
+	}
+
+	owed := t.TotalAmount
+	var cash []domain.CheckoutPayment
+	for _, p := range payments {
+		if p.Amount <= 0 {
+			return fmt.Errorf("%w: %s amount must be positive", ErrInvalidPayment, p.Method)
+		}
+		switch p.Method {
+		case domain.PaymentCash:
+			cash = append(cash, p)
+			continue
+		case domain.PaymentCard:
+		case domain.PaymentEWallet, domain.PaymentQRIS:
+			if p.Reference == "" {
+				return fmt.Errorf("%w: %s payment requires a reference", ErrInvalidPayment, p.Method)
+			}
+		default:
+			return fmt.Errorf("%w: unknown method %q", ErrInvalidPayment, p.Method)
+		}
+
+		if p.Amount > owed {
+			return fmt.Errorf("%w: %s amount %d exceeds remaining %d", ErrInvalidPayment, p.Method, p.Amount, owed)
+		}
+		owed -= p.Amount
+		t.Payments = append(t.Payments, domain.Payment{
+			Method:    p.Method,
+			Amount:    p.Amount,
+			Tendered:  p.Amount,
+			Reference: p.Reference,
+		})
+	}
+
+	for _, p := range cash {
+		if owed == 0 {
+			return fmt.Errorf("%w: %s payment of %d is not needed, the total is already covered", ErrInvalidPayment, p.Method, p.Amount)
+		}
+		applied := min(p.Amount, owed)
+		owed -= applied
+		t.Payments = append(t.Payments, domain.Payment{
+			Method:    p.Method,
+			Amount:    applied,
+			Tendered:  p.Amount,
+			Change:    p.Amount - applied,
+			Reference: p.Reference,
+		})
+	}
+
+	if owed > 0 {
+		return fmt.Errorf("%w: payments do not cover total %d, %d remaining", ErrInvalidPayment, t.TotalAmount, owed)
+	}
+	return nil
+}
@@ -3,32 +3,108 @@ package service
 import (
 	"cateogry-api/internal/domain"
 	"cateogry-api/internal/repository"
+	"cateogry-api/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidProduct is wrapped by product validation errors.
+	ErrInvalidProduct = errors.New("invalid product")
+	// ErrProductNotFound is returned for unknown or deleted products.
+	ErrProductNotFound = repository.ErrProductNotFound
 )
 
 type ProductService struct {
-	repo *repository.ProductRepository
+	repo  repository.ProductRepository
+	audit *AuditService
 }
 
-func NewProductService(repo *repository.ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+func NewProductService(repo repository.ProductRepository, audit *AuditService) *ProductService {
+	return &ProductService{repo: repo, audit: audit}
 }
 
-func (s *ProductService) GetAll(name string) ([]domain.Product, error) {
-	return s.repo.GetAll(name)
+func (s *ProductService) GetAll(ctx context.Context, name string) ([]domain.Product, error) {
+	return s.repo.GetAll(ctx, name)
 }
 
-func (s *ProductService) GetByID(id int) (*domain.Product, error) {
-	return s.repo.GetByID(id)
+// GetByCategory returns the products of a category, or ErrCategoryNotFound
+// when there is no such category.
+func (s *ProductService) GetByCategory(ctx context.Context, categoryID int) ([]domain.Product, error) {
+	return s.repo.GetByCategory(ctx, categoryID)
 }
 
-func (s *ProductService) Create(product domain.Product) (domain.Product, error) {
-	return s.repo.Create(product)
+func (s *ProductService) GetByID(ctx context.Context, id int) (*domain.Product, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ProductService) Create(ctx context.Context, product domain.Product) (_ domain.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateProduct(product); err != nil {
+		return domain.Product{}, err
+	}
+	var created domain.Product
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if created, err = s.repo.Create(ctx, product); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditCreate, "product", created.ID, nil, created)
+	})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	return created, nil
 }
 
-func (s *ProductService) Update(id int, product domain.Product) (*domain.Product, error) {
-	return s.repo.Update(id, product)
+func (s *ProductService) Update(ctx context.Context, id int, product domain.Product) (_ *domain.Product, err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Update")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validateProduct(product); err != nil {
+		return nil, err
+	}
+	var updated *domain.Product
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.Update(ctx, id, product); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditUpdate, "product", id, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *ProductService) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "ProductService.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditDelete, "product", id, before, nil)
+	})
 }
 
-func (s *ProductService) Delete(id int) error {
-	return s.repo.Delete(id)
+func validateProduct(p domain.Product) error {
+	if p.Price < 0 {
+		return fmt.Errorf("%w: price cannot be negative", ErrInvalidProduct)
+	}
+	if p.CostPrice < 0 {
+		return fmt.Errorf("%w: cost_price cannot be negative", ErrInvalidProduct)
+	}
+	return nil
 }
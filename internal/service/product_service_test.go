@@ -0,0 +1,90 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/fake"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestProductService(t *testing.T) {
+	ctx := context.Background()
+	repo := fake.NewProductRepository(
+		domain.Product{Name: "Wireless Mouse", Price: 150000, Stock: 5, CategoryID: 1},
+		domain.Product{Name: "Clean Code", Price: 450000, Stock: 2, CategoryID: 2},
+	)
+	s := NewProductService(repo, nil)
+
+	found, err := s.GetAll(ctx, "mouse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Name != "Wireless Mouse" {
+		t.Errorf("GetAll(mouse) = %+v, want the mouse only", found)
+	}
+
+	created, err := s.Create(ctx, domain.Product{Name: "USB-C Charger", Price: 250000, Stock: 3, CategoryID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 3 {
+		t.Errorf("Create ID = %d, want 3", created.ID)
+	}
+
+	updated, err := s.Update(ctx, created.ID, domain.Product{Name: "USB-C Charger", Price: 275000, Stock: 3, CategoryID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Price != 275000 {
+		t.Errorf("Update price = %d, want 275000", updated.Price)
+	}
+
+	if err := s.Delete(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetByID(ctx, created.ID); err == nil {
+		t.Error("GetByID after delete returned no error")
+	}
+	if _, err := s.Update(ctx, created.ID, *updated); err == nil {
+		t.Error("Update of a deleted product returned no error")
+	}
+	if err := s.Delete(ctx, created.ID); err == nil {
+		t.Error("Delete of a deleted product returned no error")
+	}
+}
+
+func TestProductServiceRepositoryError(t *testing.T) {
+	ctx := context.Background()
+	repo := fake.NewProductRepository(domain.Product{Name: "Wireless Mouse", Price: 150000})
+	repo.Err = fake.ErrFailed
+	s := NewProductService(repo, nil)
+
+	if _, err := s.GetAll(ctx, ""); !errors.Is(err, fake.ErrFailed) {
+		t.Errorf("GetAll error = %v, want %v", err, fake.ErrFailed)
+	}
+	if _, err := s.Create(ctx, domain.Product{Name: "Keyboard"}); !errors.Is(err, fake.ErrFailed) {
+		t.Errorf("Create error = %v, want %v", err, fake.ErrFailed)
+	}
+	if err := s.Delete(ctx, 1); !errors.Is(err, fake.ErrFailed) {
+		t.Errorf("Delete error = %v, want %v", err, fake.ErrFailed)
+	}
+}
+
+func TestProductServiceRejectsNegativePrices(t *testing.T) {
+	ctx := context.Background()
+	repo := fake.NewProductRepository(domain.Product{Name: "Wireless Mouse", Price: 150000, CostPrice: 90000})
+	s := NewProductService(repo, nil)
+
+	for _, p := range []domain.Product{
+		{Name: "Keyboard", Price: -1},
+		{Name: "Keyboard", Price: 100000, CostPrice: -1},
+	} {
+		if _, err := s.Create(ctx, p); !errors.Is(err, ErrInvalidProduct) {
+			t.Errorf("Create(price %d, cost price %d) error = %v, want %v", p.Price, p.CostPrice, err, ErrInvalidProduct)
+		}
+		if _, err := s.Update(ctx, 1, p); !errors.Is(err, ErrInvalidProduct) {
+			t.Errorf("Update(price %d, cost price %d) error = %v, want %v", p.Price, p.CostPrice, err, ErrInvalidProduct)
+		}
+	}
+}
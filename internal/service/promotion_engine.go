@@ -0,0 +1,169 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"sort"
+)
+
+// applyPromotions works out the discounts for t's lines and records them on
+// the transaction and its details.
+//
+// Stacking rules: stackable promotions are applied one after another in
+// priority order, each on what is left of a line after the previous ones.
+// A non-stackable promotion is never combined with anything; it is used on its
+// own only when it saves the customer more than the stackable set does.
+func applyPromotions(t *domain.Transaction, promotions []domain.Promotion) {
+	sorted := make([]domain.Promotion, len(promotions))
+	copy(sorted, promotions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var stackable []domain.Promotion
+	var exclusive []domain.Promotion
+	for _, p := range sorted {
+		if p.Stackable {
+			stackable = append(stackable, p)
+		} else {
+			exclusive = append(exclusive, p)
+		}
+	}
+
+	discounts, perLine, best := evaluatePromotions(t.Details, stackable)
+	for _, p := range exclusive {
+		d, l, total := evaluatePromotions(t.Details, []domain.Promotion{p})
+		if total > best {
+			discounts, perLine, best = d, l, total
+		}
+	}
+
+	for i := range t.Details {
+		t.Details[i].DiscountAmount = perLine[i]
+	}
+	t.Discounts = discounts
+	t.DiscountAmount = best
+	t.TotalAmount = t.Subtotal - best
+}
+
+// evaluatePromotions applies promotions in order and returns the itemized
+// discounts, the discount per line and the total discount.
+func evaluatePromotions(details []domain.TransactionDetail, promotions []domain.Promotion) ([]domain.AppliedDiscount, []int, int) {
+	remaining := make([]int, len(details))
+	for i, d := range details {
+		remaining[i] = d.Subtotal
+	}
+	perLine := make([]int, len(details))
+	applied := make([]domain.AppliedDiscount, 0)
+	total := 0
+
+	for _, p := range promotions {
+		amounts := promotionAmounts(p, details, remaining)
+		promoTotal := 0
+		for i, amount := range amounts {
+			amount = min(amount, remaining[i])
+			remaining[i] -= amount
+			perLine[i] += amount
+			promoTotal += amount
+		}
+		if promoTotal == 0 {
+			continue
+		}
+
+		ad := domain.AppliedDiscount{
+			PromotionID: p.ID,
+			Code:        p.Code,
+			Name:        p.Name,
+			Type:        p.Type,
+			Amount:      promoTotal,
+		}
+		if p.ProductID != nil {
+			ad.ProductID = *p.ProductID
+		}
+		applied = append(applied, ad)
+		total += promoTotal
+	}
+	return applied, perLine, total
+}
+
+func promotionAppliesTo(p domain.Promotion, d domain.TransactionDetail) bool {
+	if p.ProductID != nil && *p.ProductID != d.ProductID {
+		return false
+	}
+	if p.CategoryID != nil && *p.CategoryID != d.CategoryID {
+		return false
+	}
+	return true
+}
+
+// promotionAmounts returns the discount p would give on each line, given what
+// is left of every line after previously applied promotions.
+func promotionAmounts(p domain.Promotion, details []domain.TransactionDetail, remaining []int) []int {
+	amounts := make([]int, len(details))
+
+	switch p.Type {
+	case domain.PromotionPercentage:
+		for i, d := range details {
+			if promotionAppliesTo(p, d) {
+				amounts[i] = remaining[i] * p.Value / 100
+			}
+		}
+
+	case domain.PromotionFixed:
+		eligibleTotal := 0
+		for i, d := range details {
+			if promotionAppliesTo(p, d) {
+				eligibleTotal += remaining[i]
+			}
+		}
+		if eligibleTotal == 0 {
+			return amounts
+		}
+		off := min(p.Value, eligibleTotal)
+
+		// Spread the amount over eligible lines by their share of the
+		// eligible total; the rounding remainder goes to the last line.
+		allocated, last := 0, -1
+		for i, d := range details {
+			if promotionAppliesTo(p, d) && remaining[i] > 0 {
+				amounts[i] = off * remaining[i] / eligibleTotal
+				allocated += amounts[i]
+				last = i
+			}
+		}
+		amounts[last] += off - allocated
+
+	case domain.PromotionBuyXGetY:
+		group := p.BuyQuantity + p.GetQuantity
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return amounts
+		}
+
+		var eligible []int
+		quantity := 0
+		for i, d := range details {
+			if promotionAppliesTo(p, d) {
+				eligible = append(eligible, i)
+				quantity += d.Quantity
+			}
+		}
+
+		// The cheapest units are the free ones.
+		sort.SliceStable(eligible, func(a, b int) bool {
+			return details[eligible[a]].UnitPrice < details[eligible[b]].UnitPrice
+		})
+		free := quantity / group * p.GetQuantity
+		for _, i := range eligible {
+			if free == 0 {
+				break
+			}
+			n := min(free, details[i].Quantity)
+			amounts[i] = n * details[i].UnitPrice
+			free -= n
+		}
+	}
+
+	return amounts
+}
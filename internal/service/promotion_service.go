@@ -0,0 +1,76 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPromotion is wrapped by every validation error returned from
+// PromotionService, so handlers can answer 400 instead of 500.
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+type PromotionService struct {
+	repo repository.PromotionRepository
+}
+
+func NewPromotionService(repo repository.PromotionRepository) *PromotionService {
+	return &PromotionService{repo: repo}
+}
+
+func (s *PromotionService) GetAll(ctx context.Context) ([]domain.Promotion, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *PromotionService) GetByID(ctx context.Context, id int) (*domain.Promotion, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *PromotionService) Create(ctx context.Context, p domain.Promotion) (domain.Promotion, error) {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	if err := validatePromotion(p); err != nil {
+		return domain.Promotion{}, err
+	}
+	return s.repo.Create(ctx, p)
+}
+
+func (s *PromotionService) Update(ctx context.Context, id int, p domain.Promotion) (*domain.Promotion, error) {
+	p.Code = strings.ToUpper(strings.TrimSpace(p.Code))
+	if err := validatePromotion(p); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, p)
+}
+
+func (s *PromotionService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func validatePromotion(p domain.Promotion) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidPromotion)
+	}
+	switch p.Type {
+	case domain.PromotionPercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return fmt.Errorf("%w: percentage value must be between 1 and 100", ErrInvalidPromotion)
+		}
+	case domain.PromotionFixed:
+		if p.Value < 1 {
+			return fmt.Errorf("%w: fixed value must be positive", ErrInvalidPromotion)
+		}
+	case domain.PromotionBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return fmt.Errorf("%w: buy_quantity and get_quantity must be positive", ErrInvalidPromotion)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidPromotion, p.Type)
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidPromotion)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidPurchaseOrder is wrapped by purchase order validation errors.
+	ErrInvalidPurchaseOrder = errors.New("invalid purchase order")
+	// ErrPurchaseOrderNotFound is returned for unknown purchase orders.
+	ErrPurchaseOrderNotFound = repository.ErrPurchaseOrderNotFound
+	// ErrPurchaseOrderStatus is returned when an order's status does not
+	// allow the change.
+	ErrPurchaseOrderStatus = repository.ErrPurchaseOrderStatus
+	// ErrInvalidReceipt is returned when received items are not on the order
+	// or exceed what is still outstanding.
+	ErrInvalidReceipt = repository.ErrInvalidReceipt
+)
+
+type PurchaseOrderService struct {
+	repo *repository.PostgresPurchaseOrderRepository
+}
+
+func NewPurchaseOrderService(repo *repository.PostgresPurchaseOrderRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{repo: repo}
+}
+
+func (s *PurchaseOrderService) GetAll(ctx context.Context, status domain.PurchaseOrderStatus) ([]domain.PurchaseOrder, error) {
+	switch status {
+	case "", domain.PurchaseOrderDraft, domain.PurchaseOrderOrdered, domain.PurchaseOrderPartiallyReceived, domain.PurchaseOrderReceived:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidPurchaseOrder, status)
+	}
+	return s.repo.GetAll(ctx, status)
+}
+
+func (s *PurchaseOrderService) GetByID(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *PurchaseOrderService) Create(ctx context.Context, po domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+	if err := validatePurchaseOrder(po); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, po)
+}
+
+func (s *PurchaseOrderService) Update(ctx context.Context, id int, po domain.PurchaseOrder) (*domain.PurchaseOrder, error) {
+	if err := validatePurchaseOrder(po); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, po)
+}
+
+func (s *PurchaseOrderService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Order sends a draft purchase order to the supplier. It can no longer be
+// edited afterwards.
+func (s *PurchaseOrderService) Order(ctx context.Context, id int) (*domain.PurchaseOrder, error) {
+	return s.repo.MarkOrdered(ctx, id)
+}
+
+// Receive adds the arrived quantities to stock at the order's location.
+func (s *PurchaseOrderService) Receive(ctx context.Context, id int, req domain.ReceiveRequest) (*domain.PurchaseOrder, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: items are required", ErrInvalidPurchaseOrder)
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity for product id %d must be positive", ErrInvalidPurchaseOrder, item.ProductID)
+		}
+	}
+	return s.repo.Receive(ctx, id, req.Items)
+}
+
+func validatePurchaseOrder(po domain.PurchaseOrder) error {
+	if po.SupplierID <= 0 {
+		return fmt.Errorf("%w: supplier_id is required", ErrInvalidPurchaseOrder)
+	}
+	if len(po.Items) == 0 {
+		return fmt.Errorf("%w: items are required", ErrInvalidPurchaseOrder)
+	}
+	seen := make(map[int]bool, len(po.Items))
+	for _, item := range po.Items {
+		if seen[item.ProductID] {
+			return fmt.Errorf("%w: product id %d is listed twice", ErrInvalidPurchaseOrder, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if item.QuantityOrdered <= 0 {
+			return fmt.Errorf("%w: quantity_ordered for product id %d must be positive", ErrInvalidPurchaseOrder, item.ProductID)
+		}
+		if item.UnitCost < 0 {
+			return fmt.Errorf("%w: unit_cost for product id %d cannot be negative", ErrInvalidPurchaseOrder, item.ProductID)
+		}
+	}
+	return nil
+}
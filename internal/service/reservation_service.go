@@ -0,0 +1,73 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidReservation is wrapped by reservation validation errors.
+	ErrInvalidReservation = errors.New("invalid reservation")
+	// ErrReservationNotFound is returned for unknown reservations, for
+	// reservations of another caller and for releasing one that is no longer
+	// active.
+	ErrReservationNotFound = repository.ErrReservationNotFound
+	// ErrReservationNotActive is returned when checking out against a
+	// reservation that was consumed, released or has expired.
+	ErrReservationNotActive = repository.ErrReservationNotActive
+	// ErrNotReserved is returned when a checkout asks for more than its
+	// reservation holds.
+	ErrNotReserved = repository.ErrNotReserved
+)
+
+type ReservationService struct {
+	repo       *repository.PostgresReservationRepository
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+}
+
+// NewReservationService creates a ReservationService. Reservations last
+// defaultTTL unless the request asks for another TTL, which is capped at maxTTL.
+func NewReservationService(repo *repository.PostgresReservationRepository, defaultTTL, maxTTL time.Duration) *ReservationService {
+	return &ReservationService{repo: repo, defaultTTL: defaultTTL, maxTTL: maxTTL}
+}
+
+func (s *ReservationService) Create(ctx context.Context, req domain.ReservationRequest) (*domain.StockReservation, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: items are required", ErrInvalidReservation)
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity for product id %d must be positive", ErrInvalidReservation, item.ProductID)
+		}
+	}
+
+	ttl := s.defaultTTL
+	if req.TTLSeconds < 0 {
+		return nil, fmt.Errorf("%w: ttl_seconds must not be negative", ErrInvalidReservation)
+	}
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	ttl = min(ttl, s.maxTTL)
+
+	return s.repo.Create(ctx, req.Items, ttl, callerSubject(ctx))
+}
+
+func (s *ReservationService) GetByID(ctx context.Context, id int) (*domain.StockReservation, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ReservationService) Release(ctx context.Context, id int) error {
+	return s.repo.Release(ctx, id, callerSubject(ctx))
+}
+
+// ExpireStale releases every reservation past its TTL and returns how many
+// there were.
+func (s *ReservationService) ExpireStale(ctx context.Context) (int64, error) {
+	return s.repo.ExpireStale(ctx)
+}
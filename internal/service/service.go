@@ -3,32 +3,79 @@ package service
 import (
 	"cateogry-api/internal/domain"
 	"cateogry-api/internal/repository"
+	"cateogry-api/internal/tracing"
+	"context"
 )
 
+var ErrCategoryNotFound = repository.ErrCategoryNotFound
+
 type CategoryService struct {
-	repo repository.CategoryRepository
+	repo  repository.CategoryRepository
+	audit *AuditService
 }
 
-func NewCategoryService(repo repository.CategoryRepository) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepository, audit *AuditService) *CategoryService {
+	return &CategoryService{repo: repo, audit: audit}
 }
 
-func (s *CategoryService) GetAllCategories() ([]domain.Category, error) {
-	return s.repo.GetAll()
+func (s *CategoryService) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
+	return s.repo.GetAll(ctx)
 }
 
-func (s *CategoryService) GetCategoryByID(id int) (*domain.Category, error) {
-	return s.repo.GetByID(id)
+func (s *CategoryService) GetCategoryByID(ctx context.Context, id int) (*domain.Category, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *CategoryService) CreateCategory(c domain.Category) (domain.Category, error) {
-	return s.repo.Create(c)
+func (s *CategoryService) CreateCategory(ctx context.Context, c domain.Category) (_ domain.Category, err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateCategory")
+	defer func() { tracing.End(span, err) }()
+
+	var created domain.Category
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		if created, err = s.repo.Create(ctx, c); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditCreate, "category", created.ID, nil, created)
+	})
+	if err != nil {
+		return domain.Category{}, err
+	}
+	return created, nil
 }
 
-func (s *CategoryService) UpdateCategory(id int, c domain.Category) (*domain.Category, error) {
-	return s.repo.Update(id, c)
+func (s *CategoryService) UpdateCategory(ctx context.Context, id int, c domain.Category) (_ *domain.Category, err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.UpdateCategory")
+	defer func() { tracing.End(span, err) }()
+
+	var updated *domain.Category
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated, err = s.repo.Update(ctx, id, c); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditUpdate, "category", id, before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
-func (s *CategoryService) DeleteCategory(id int) error {
-	return s.repo.Delete(id)
+func (s *CategoryService) DeleteCategory(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.DeleteCategory")
+	defer func() { tracing.End(span, err) }()
+
+	return s.audit.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditDelete, "category", id, before, nil)
+	})
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"testing"
+)
+
+func TestCategoryService(t *testing.T) {
+	ctx := context.Background()
+	s := NewCategoryService(repository.NewInMemoryCategoryRepository(), nil)
+
+	all, err := s.GetAllCategories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seeded := len(all)
+
+	created, err := s.CreateCategory(ctx, domain.Category{Name: "Toys", Description: "For kids"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == 0 || created.Name != "Toys" {
+		t.Fatalf("CreateCategory = %+v", created)
+	}
+
+	updated, err := s.UpdateCategory(ctx, created.ID, domain.Category{Name: "Games", Description: "For everyone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Games" {
+		t.Errorf("UpdateCategory name = %q, want Games", updated.Name)
+	}
+	got, err := s.GetCategoryByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != "For everyone" {
+		t.Errorf("GetCategoryByID description = %q, want For everyone", got.Description)
+	}
+
+	if err := s.DeleteCategory(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetCategoryByID(ctx, created.ID); err == nil {
+		t.Error("GetCategoryByID after delete returned no error")
+	}
+	if all, _ := s.GetAllCategories(ctx); len(all) != seeded {
+		t.Errorf("GetAllCategories returned %d categories, want %d", len(all), seeded)
+	}
+
+	if _, err := s.UpdateCategory(ctx, created.ID, domain.Category{Name: "Gone"}); err == nil {
+		t.Error("UpdateCategory of a deleted category returned no error")
+	}
+	if err := s.DeleteCategory(ctx, created.ID); err == nil {
+		t.Error("DeleteCategory of a deleted category returned no error")
+	}
+}
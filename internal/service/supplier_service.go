@@ -0,0 +1,35 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+)
+
+type SupplierService struct {
+	repo *repository.PostgresSupplierRepository
+}
+
+func NewSupplierService(repo *repository.PostgresSupplierRepository) *SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+func (s *SupplierService) GetAll(ctx context.Context) ([]domain.Supplier, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *SupplierService) GetByID(ctx context.Context, id int) (*domain.Supplier, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *SupplierService) Create(ctx context.Context, supplier domain.Supplier) (domain.Supplier, error) {
+	return s.repo.Create(ctx, supplier)
+}
+
+func (s *SupplierService) Update(ctx context.Context, id int, supplier domain.Supplier) (*domain.Supplier, error) {
+	return s.repo.Update(ctx, id, supplier)
+}
+
+func (s *SupplierService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidTaxRate is wrapped by every validation error returned from TaxService.
+	ErrInvalidTaxRate = errors.New("invalid tax rate")
+	// ErrTaxRateNotFound is returned for unknown tax rate IDs.
+	ErrTaxRateNotFound = repository.ErrTaxRateNotFound
+)
+
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundDown     RoundingMode = "down"
+	RoundUp       RoundingMode = "up"
+)
+
+// ParseRoundingMode accepts one of the RoundingMode values; an empty string
+// means RoundHalfUp.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return RoundHalfUp, nil
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", s)
+	}
+}
+
+// TaxSettings control how tax rates are applied at checkout. When
+// PricesIncludeTax is true product prices already contain tax and it is
+// extracted from them; otherwise tax is added on top.
+type TaxSettings struct {
+	PricesIncludeTax bool
+	Rounding         RoundingMode
+}
+
+type TaxService struct {
+	repo repository.TaxRateRepository
+}
+
+func NewTaxService(repo repository.TaxRateRepository) *TaxService {
+	return &TaxService{repo: repo}
+}
+
+func (s *TaxService) GetAll(ctx context.Context) ([]domain.TaxRate, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *TaxService) GetByID(ctx context.Context, id int) (*domain.TaxRate, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *TaxService) Create(ctx context.Context, t domain.TaxRate) (domain.TaxRate, error) {
+	if err := validateTaxRate(t); err != nil {
+		return domain.TaxRate{}, err
+	}
+	return s.repo.Create(ctx, t)
+}
+
+func (s *TaxService) Update(ctx context.Context, id int, t domain.TaxRate) (*domain.TaxRate, error) {
+	if err := validateTaxRate(t); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, t)
+}
+
+func (s *TaxService) Delete(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func validateTaxRate(t domain.TaxRate) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTaxRate)
+	}
+	if t.Rate < 0 || t.Rate > 10000 {
+		return fmt.Errorf("%w: rate must be between 0 and 10000 basis points", ErrInvalidTaxRate)
+	}
+	return nil
+}
+
+// applyTax computes tax per line on the discounted line amount and updates the
+// transaction totals. Each line uses its category's rate, falling back to the
+// default rate (the one without a category).
+func applyTax(t *domain.Transaction, rates []domain.TaxRate, settings TaxSettings) {
+	defaultRate := 0
+	byCategory := make(map[int]int, len(rates))
+	for _, r := range rates {
+		if r.CategoryID == nil {
+			defaultRate = r.Rate
+		} else {
+			byCategory[*r.CategoryID] = r.Rate
+		}
+	}
+
+	t.TaxInclusive = settings.PricesIncludeTax
+	t.TaxAmount = 0
+	for i := range t.Details {
+		d := &t.Details[i]
+		rate, ok := byCategory[d.CategoryID]
+		if !ok {
+			rate = defaultRate
+		}
+		base := d.Subtotal - d.DiscountAmount
+
+		d.TaxRate = rate
+		if settings.PricesIncludeTax {
+			d.TaxAmount = roundDiv(base*rate, 10000+rate, settings.Rounding)
+		} else {
+			d.TaxAmount = roundDiv(base*rate, 10000, settings.Rounding)
+		}
+		t.TaxAmount += d.TaxAmount
+	}
+
+	t.TotalAmount = t.Subtotal - t.DiscountAmount
+	if !settings.PricesIncludeTax {
+		t.TotalAmount += t.TaxAmount
+	}
+}
+
+// roundDiv returns num/den rounded with mode. Both arguments are non-negative.
+func roundDiv(num, den int, mode RoundingMode) int {
+	q, rem := num/den, num%den
+	if rem == 0 {
+		return q
+	}
+	switch mode {
+	case RoundDown:
+		return q
+	case RoundUp:
+		return q + 1
+	case RoundHalfEven:
+		if 2*rem > den || (2*rem == den && q%2 == 1) {
+			return q + 1
+		}
+		return q
+	default:
+		if 2*rem >= den {
+			return q + 1
+		}
+		return q
+	}
+}
@@ -2,26 +2,169 @@ package service
 
 import (
 	"cateogry-api/internal/domain"
+	"cateogry-api/internal/logging"
+	"cateogry-api/internal/metrics"
 	"cateogry-api/internal/repository"
+	"cateogry-api/internal/tracing"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// ErrInvalidCheckout is wrapped by checkout validation errors.
+	ErrInvalidCheckout = errors.New("invalid checkout")
+	// ErrInvalidPromoCode is wrapped when a promo code is unknown, expired or
+	// not yet valid.
+	ErrInvalidPromoCode = errors.New("invalid promo code")
+	// ErrInsufficientStock is returned when a product does not have enough
+	// unreserved stock.
+	ErrInsufficientStock = repository.ErrInsufficientStock
+	// ErrTransactionNotFound is returned for unknown transactions.
+	ErrTransactionNotFound = repository.ErrTransactionNotFound
+	// ErrIdempotencyKeyUsed is returned when a checkout reuses an idempotency
+	// key of another caller, or of a different checkout.
+	ErrIdempotencyKeyUsed = repository.ErrIdempotencyKeyUsed
 )
 
 type TransactionService struct {
-	repo *repository.TransactionRepository
+	repo          repository.TransactionRepository
+	promotionRepo repository.PromotionRepository
+	taxRepo       repository.TaxRateRepository
+	taxSettings   TaxSettings
+	audit         *AuditService
+}
+
+func NewTransactionService(repo repository.TransactionRepository, promotionRepo repository.PromotionRepository, taxRepo repository.TaxRateRepository, taxSettings TaxSettings, audit *AuditService) *TransactionService {
+	return &TransactionService{repo: repo, promotionRepo: promotionRepo, taxRepo: taxRepo, taxSettings: taxSettings, audit: audit}
+}
+
+func (s *TransactionService) Checkout(ctx context.Context, req domain.CheckoutRequest) (_ *domain.Transaction, err error) {
+	ctx, span := tracing.Start(ctx, "TransactionService.Checkout",
+		attribute.Int("checkout.items", len(req.Items)),
+		attribute.Int("checkout.location_id", req.LocationID))
+	defer func() { tracing.End(span, err) }()
+
+	if req.CartID == 0 {
+		if err := validateCheckoutItems(req.Items); err != nil {
+			return nil, err
+		}
+	}
+	req.Caller = callerSubject(ctx)
+	if req.RequestHash, err = checkoutHash(req); err != nil {
+		return nil, err
+	}
+
+	promotions, err := s.promotionsFor(ctx, req.PromoCodes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	taxRates, err := s.taxRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *domain.Transaction
+	err = s.audit.Transaction(ctx, func(ctx context.Context) error {
+		t, err = s.repo.CreateTransaction(ctx, req, func(t *domain.Transaction) error {
+			applyPromotions(t, promotions)
+			applyTax(t, taxRates, s.taxSettings)
+			return applyPayments(t, req.Payments)
+		})
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, domain.AuditCreate, "transaction", t.ID, nil, t)
+	})
+	if errors.Is(err, repository.ErrIdempotencyKeyReplayed) {
+		// A retry of a checkout that went through: answer as the first time
+		span.SetAttributes(attribute.Bool("checkout.replayed", true))
+		return s.repo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("transaction.id", t.ID))
+	metrics.CheckoutCompleted(t.TotalAmount)
+	logging.FromContext(ctx).Info("Checkout completed", "transaction_id", t.ID, "total_amount", t.TotalAmount)
+	return t, nil
+}
+
+// checkoutHash returns the hex SHA-256 of what req asks for, so a retry can
+// be told from another checkout reusing its idempotency key.
+func checkoutHash(req domain.CheckoutRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(fmt.Appendf(body, "\ncart_id=%d", req.CartID))
+	return hex.EncodeToString(sum[:]), nil
 }
 
-func NewTransactionService(repo *repository.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+// validateCheckoutItems requires at least one item, each product at most once
+// and every quantity positive.
+func validateCheckoutItems(items []domain.CheckoutItem) error {
+	if len(items) == 0 {
+		return fmt.Errorf("%w: items are required", ErrInvalidCheckout)
+	}
+	seen := make(map[int]bool, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity for product id %d must be positive", ErrInvalidCheckout, item.ProductID)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("%w: product id %d is listed more than once", ErrInvalidCheckout, item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
+}
+
+// promotionsFor returns the automatic promotions valid at t plus the ones
+// unlocked by codes. Every code must match a currently valid promotion.
+func (s *TransactionService) promotionsFor(ctx context.Context, codes []string, t time.Time) ([]domain.Promotion, error) {
+	valid, err := s.promotionRepo.GetValidAt(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		requested[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	promotions := make([]domain.Promotion, 0, len(valid))
+	for _, p := range valid {
+		if p.Code == "" {
+			promotions = append(promotions, p)
+			continue
+		}
+		if requested[p.Code] {
+			promotions = append(promotions, p)
+			delete(requested, p.Code)
+		}
+	}
+	for code := range requested {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPromoCode, code)
+	}
+	return promotions, nil
 }
 
-func (s *TransactionService) Checkout(items []domain.CheckoutItem) (*domain.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+func (s *TransactionService) GetByID(ctx context.Context, id int) (*domain.Transaction, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *TransactionService) GetDailyReport(date time.Time) (domain.DailyReport, error) {
-	return s.repo.GetDailyReport(date)
+func (s *TransactionService) GetDailyReport(ctx context.Context, date time.Time) (domain.DailyReport, error) {
+	return s.repo.GetDailyReport(ctx, date)
 }
 
-func (s *TransactionService) GetReport(startDate, endDate time.Time) (domain.DailyReport, error) {
-	return s.repo.GetReport(startDate, endDate)
+func (s *TransactionService) GetReport(ctx context.Context, startDate, endDate time.Time) (domain.DailyReport, error) {
+	return s.repo.GetReport(ctx, startDate, endDate)
 }
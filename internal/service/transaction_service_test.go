@@ -0,0 +1,274 @@
+package service
+
+import (
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/fake"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type checkoutFixture struct {
+	products     *fake.ProductRepository
+	transactions *fake.TransactionRepository
+	service      *TransactionService
+}
+
+// newCheckoutFixture stocks a mouse (150000, category 1) and a book (450000,
+// category 2), with an 11% default tax, 0% tax on books, an automatic 10%
+// off books and a HEMAT25K code for 25000 off.
+func newCheckoutFixture(settings TaxSettings) checkoutFixture {
+	books := 2
+	products := fake.NewProductRepository(
+		domain.Product{Name: "Wireless Mouse", Price: 150000, CostPrice: 95000, Stock: 10, CategoryID: 1},
+		domain.Product{Name: "Clean Code", Price: 450000, CostPrice: 300000, Stock: 3, CategoryID: books},
+	)
+	transactions := fake.NewTransactionRepository(products)
+	promotions := fake.NewPromotionRepository(
+		domain.Promotion{Name: "Book week", Type: domain.PromotionPercentage, Value: 10, CategoryID: &books, Stackable: true, Active: true},
+		domain.Promotion{Code: "HEMAT25K", Name: "Hemat 25K", Type: domain.PromotionFixed, Value: 25000, Stackable: true, Active: true},
+	)
+	taxes := fake.NewTaxRateRepository(
+		domain.TaxRate{Name: "PPN", Rate: 1100},
+		domain.TaxRate{Name: "Books", CategoryID: &books, Rate: 0},
+	)
+	return checkoutFixture{
+		products:     products,
+		transactions: transactions,
+		service:      NewTransactionService(transactions, promotions, taxes, settings, nil),
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(TaxSettings{Rounding: RoundHalfUp})
+
+	tx, err := f.service.Checkout(ctx, domain.CheckoutRequest{
+		Items: []domain.CheckoutItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Mouse: 300000, taxed 11%. Book: 450000 less 10%, untaxed.
+	want := domain.Transaction{Subtotal: 750000, DiscountAmount: 45000, TaxAmount: 33000, TotalAmount: 738000}
+	if tx.Subtotal != want.Subtotal || tx.DiscountAmount != want.DiscountAmount ||
+		tx.TaxAmount != want.TaxAmount || tx.TotalAmount != want.TotalAmount {
+		t.Errorf("Checkout totals = %d/%d/%d/%d, want %d/%d/%d/%d",
+			tx.Subtotal, tx.DiscountAmount, tx.TaxAmount, tx.TotalAmount,
+			want.Subtotal, want.DiscountAmount, want.TaxAmount, want.TotalAmount)
+	}
+	if len(tx.Payments) != 1 || tx.Payments[0].Method != domain.PaymentCash || tx.Payments[0].Amount != 738000 || tx.Payments[0].Change != 0 {
+		t.Errorf("Checkout without payments recorded %+v, want exact cash", tx.Payments)
+	}
+
+	mouse, _ := f.products.GetByID(ctx, 1)
+	if mouse.Stock != 8 {
+		t.Errorf("mouse stock after checkout = %d, want 8", mouse.Stock)
+	}
+}
+
+func TestCheckoutTaxInclusive(t *testing.T) {
+	f := newCheckoutFixture(TaxSettings{PricesIncludeTax: true, Rounding: RoundDown})
+
+	tx, err := f.service.Checkout(context.Background(), domain.CheckoutRequest{
+		Items: []domain.CheckoutItem{{ProductID: 1, Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 150000 * 1100 / 11100 = 14864.86...
+	if tx.TaxAmount != 14864 || tx.TotalAmount != 150000 || !tx.TaxInclusive {
+		t.Errorf("tax/total/inclusive = %d/%d/%v, want 14864/150000/true", tx.TaxAmount, tx.TotalAmount, tx.TaxInclusive)
+	}
+}
+
+func TestCheckoutPromoCode(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(TaxSettings{})
+
+	tx, err := f.service.Checkout(ctx, domain.CheckoutRequest{
+		Items:      []domain.CheckoutItem{{ProductID: 1, Quantity: 1}},
+		PromoCodes: []string{" hemat25k "},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.DiscountAmount != 25000 || len(tx.Discounts) != 1 {
+		t.Errorf("discount = %d in %d discounts, want 25000 in 1", tx.DiscountAmount, len(tx.Discounts))
+	}
+
+	_, err = f.service.Checkout(ctx, domain.CheckoutRequest{
+		Items:      []domain.CheckoutItem{{ProductID: 1, Quantity: 1}},
+		PromoCodes: []string{"NOPE"},
+	})
+	if !errors.Is(err, ErrInvalidPromoCode) {
+		t.Errorf("Checkout with an unknown promo code: error = %v, want %v", err, ErrInvalidPromoCode)
+	}
+}
+
+func TestCheckoutPayments(t *testing.T) {
+	tests := []struct {
+		name       string
+		payments   []domain.CheckoutPayment
+		wantErr    bool
+		wantChange int
+	}{
+		{"exact cash", []domain.CheckoutPayment{{Method: domain.PaymentCash, Amount: 166500}}, false, 0},
+		{"cash with change", []domain.CheckoutPayment{{Method: domain.PaymentCash, Amount: 200000}}, false, 33500},
+		{"card and cash", []domain.CheckoutPayment{
+			{Method: domain.PaymentCash, Amount: 20000},
+			{Method: domain.PaymentCard, Amount: 150000},
+		}, false, 3500},
+		{"short", []domain.CheckoutPayment{{Method: domain.PaymentCash, Amount: 100000}}, true, 0},
+		{"cash after covered", []domain.CheckoutPayment{
+			{Method: domain.PaymentCash, Amount: 200000},
+			{Method: domain.PaymentCash, Amount: 50000},
+		}, true, 0},
+		{"cash after card covered", []domain.CheckoutPayment{
+			{Method: domain.PaymentCard, Amount: 166500},
+			{Method: domain.PaymentCash, Amount: 50000},
+		}, true, 0},
+		{"card over total", []domain.CheckoutPayment{{Method: domain.PaymentCard, Amount: 200000}}, true, 0},
+		{"qris without reference", []domain.CheckoutPayment{{Method: domain.PaymentQRIS, Amount: 166500}}, true, 0},
+		{"unknown method", []domain.CheckoutPayment{{Method: "cheque", Amount: 166500}}, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newCheckoutFixture(TaxSettings{})
+
+			// 150000 plus 11% tax is 166500
+			tx, err := f.service.Checkout(ctx, domain.CheckoutRequest{
+				Items:    []domain.CheckoutItem{{ProductID: 1, Quantity: 1}},
+				Payments: tt.payments,
+			})
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPayment) {
+					t.Fatalf("Checkout error = %v, want %v", err, ErrInvalidPayment)
+				}
+				if mouse, _ := f.products.GetByID(ctx, 1); mouse.Stock != 10 {
+					t.Errorf("stock after a rejected checkout = %d, want 10", mouse.Stock)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			change := 0
+			for _, p := range tx.Payments {
+				change += p.Change
+			}
+			if change != tt.wantChange {
+				t.Errorf("change = %d, want %d", change, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestCheckoutOutOfStock(t *testing.T) {
+	f := newCheckoutFixture(TaxSettings{})
+
+	_, err := f.service.Checkout(context.Background(), domain.CheckoutRequest{
+		Items: []domain.CheckoutItem{{ProductID: 2, Quantity: 4}},
+	})
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Checkout of more than the stock: error = %v, want %v", err, ErrInsufficientStock)
+	}
+}
+
+func TestCheckoutInvalidItems(t *testing.T) {
+	tests := map[string][]domain.CheckoutItem{
+		"no items":      nil,
+		"zero quantity": {{ProductID: 1, Quantity: 0}},
+		"negative":      {{ProductID: 1, Quantity: -1}},
+		"duplicate":     {{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 1}},
+	}
+	for name, items := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newCheckoutFixture(TaxSettings{})
+			_, err := f.service.Checkout(context.Background(), domain.CheckoutRequest{Items: items})
+			if !errors.Is(err, ErrInvalidCheckout) {
+				t.Errorf("Checkout error = %v, want %v", err, ErrInvalidCheckout)
+			}
+		})
+	}
+}
+
+func TestCheckoutIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(TaxSettings{})
+	req := domain.CheckoutRequest{
+		Items:          []domain.CheckoutItem{{ProductID: 1, Quantity: 1}},
+		IdempotencyKey: "order-42",
+	}
+
+	first, err := f.service.Checkout(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := f.service.Checkout(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || again.TotalAmount != first.TotalAmount {
+		t.Errorf("replayed checkout = #%d %d, want #%d %d", again.ID, again.TotalAmount, first.ID, first.TotalAmount)
+	}
+	if mouse, _ := f.products.GetByID(ctx, 1); mouse.Stock != 9 {
+		t.Errorf("stock after a replayed checkout = %d, want 9", mouse.Stock)
+	}
+
+	changed := req
+	changed.Items = []domain.CheckoutItem{{ProductID: 1, Quantity: 2}}
+	if _, err := f.service.Checkout(ctx, changed); !errors.Is(err, ErrIdempotencyKeyUsed) {
+		t.Errorf("different checkout with a used key: error = %v, want %v", err, ErrIdempotencyKeyUsed)
+	}
+	otherCaller := WithPrincipal(ctx, &domain.Principal{Subject: "jwt:bob", Method: "jwt"})
+	if _, err := f.service.Checkout(otherCaller, req); !errors.Is(err, ErrIdempotencyKeyUsed) {
+		t.Errorf("another caller's key: error = %v, want %v", err, ErrIdempotencyKeyUsed)
+	}
+
+	req.IdempotencyKey = "order-43"
+	other, err := f.service.Checkout(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.ID == first.ID {
+		t.Error("checkout with a new key returned the earlier transaction")
+	}
+}
+
+func TestTransactionServiceReport(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(TaxSettings{})
+	for range 2 {
+		if _, err := f.service.Checkout(ctx, domain.CheckoutRequest{
+			Items: []domain.CheckoutItem{{ProductID: 1, Quantity: 1}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := f.service.GetDailyReport(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalTransactions != 2 || report.TotalRevenue != 333000 || report.TotalTax != 33000 {
+		t.Errorf("report = %d transactions, %d revenue, %d tax; want 2, 333000, 33000",
+			report.TotalTransactions, report.TotalRevenue, report.TotalTax)
+	}
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	report, err = f.service.GetReport(ctx, yesterday.Add(-time.Hour), yesterday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalTransactions != 0 {
+		t.Errorf("report of yesterday has %d transactions, want 0", report.TotalTransactions)
+	}
+
+	f.transactions.Err = fake.ErrFailed
+	if _, err := f.service.GetByID(ctx, 1); !errors.Is(err, fake.ErrFailed) {
+		t.Errorf("GetByID error = %v, want %v", err, fake.ErrFailed)
+	}
+}
@@ -1,28 +1,18 @@
 package main
 
 import (
-	"cateogry-api/database"
-	"cateogry-api/internal/handler"
-	"cateogry-api/internal/repository"
-	"cateogry-api/internal/service"
+	"cateogry-api/internal/config"
+	"cateogry-api/internal/logging"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
-	"net/http"
+	"log/slog"
 	"os"
 	"strings"
-	"time"
-
-	_ "cateogry-api/docs" // Import generated docs
-
-	"github.com/spf13/viper"
-	httpSwagger "github.com/swaggo/http-swagger"
+	"text/tabwriter"
 )
 
-type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
-}
-
 //	@title			Category & Product API
 //	@version		1.0
 //	@description	This is a sample server for managing categories and products.
@@ -42,87 +32,174 @@ type Config struct {
 //	@BasePath	/api/v1
 //	@schemes	http
 
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				API key created through /api-keys
+
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				"Bearer " followed by an HS256 or RS256 JWT
+
+//	@security	ApiKeyAuth
+//	@security	BearerAuth
+
+// command is a subcommand of the binary. run gets the validated
+// configuration and the arguments after the command name.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, cfg config.Config, args []string) error
+}
+
+var commands = []command{
+	{"serve", "", "run the HTTP API and its background jobs (default)", serve},
+	{"migrate", "up|down|status", "apply, revert or list schema migrations", migrate},
+	{"seed", "", "insert the demo categories and sample products", seed},
+	{"cleanup", "", "purge soft-deleted records and expired carts", cleanup},
+	{"report", "", "write a sales report to stdout", report},
+	{"check-db", "", "check that the database is reachable and migrated", checkDB},
+}
+
+// errUsage is returned by commands given invalid arguments, once the problem
+// and the usage have been printed.
+var errUsage = errors.New("invalid usage")
+
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file (default: config.yaml, config.yml or config.toml if present)")
+	flag.Usage = usage
+	flag.Parse()
+
 	// Setup Configuration
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	loaded, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:", err)
+		os.Exit(1)
+	}
 
-	if _, err := os.Stat(".env"); err == nil {
-		viper.SetConfigFile(".env")
-		if err := viper.ReadInConfig(); err != nil {
-			log.Println("Error reading config file, using environment variables", err)
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	if args[0] == "config" {
+		if len(args) == 2 && args[1] == "print" {
+			printConfig(loaded)
+			return
+		}
+		unknownCommand(args)
+	}
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
 		}
 	}
+	if cmd == nil {
+		unknownCommand(args)
+	}
 
-	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+	if err := loaded.Validate(); err != nil {
+		fmt.Fprint(os.Stderr, "Invalid configuration:\n"+bulletList(err))
+		os.Exit(1)
 	}
+	cfg := loaded.Config
 
-	// Setup Database
-	// Setup Database
-	db, err := database.InitDB(config.DBConn)
+	// Setup Logging
+	logger, err := logging.New(os.Stderr, cfg.Log.Level, cfg.Log.Format)
 	if err != nil {
-		log.Println("Warning: Failed to initialize database connection:", err)
-		// We continue so the health check endpoint can report the error
-	} else {
-		defer db.Close()
-	}
-
-	// Health Check
-	healthHandler := handler.NewHealthHandler(db)
-
-	// Category Dependency Injection
-	categoryRepo := repository.NewPostgresCategoryRepository(db)
-	categorySvc := service.NewCategoryService(categoryRepo)
-	categoryHandler := handler.NewCategoryHandler(categorySvc)
-
-	// Product Dependency Injection
-	productRepo := repository.NewProductRepository(db)
-	productSvc := service.NewProductService(productRepo)
-	productHandler := handler.NewProductHandler(productSvc)
-
-	// Transaction Dependency Injection
-	transactionRepo := repository.NewTransactionRepository(db)
-	transactionSvc := service.NewTransactionService(transactionRepo)
-	transactionHandler := handler.NewTransactionHandler(transactionSvc)
-
-	// API Versioning Setup
-	v1Mux := http.NewServeMux()
-	v1Mux.HandleFunc("/health", healthHandler.Check)
-	categoryHandler.RegisterRoutes(v1Mux)
-	productHandler.RegisterRoutes(v1Mux)
-	transactionHandler.RegisterRoutes(v1Mux)
-
-	// Main Router
-	mux := http.NewServeMux()
-	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", v1Mux))
-
-	// Swagger Setup (Root Level)
-	mux.Handle("/swagger/", httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:8080/swagger/doc.json"), // The url pointing to API definition
-	))
-
-	// Start Background Cleanup Routine (Every 24 hours, delete records older than 30 days)
-	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
-		defer ticker.Stop()
-		for range ticker.C {
-			log.Println("Running cleanup routine...")
-			duration := 30 * 24 * time.Hour
-			if err := categoryRepo.CleanUpOldDeleted(duration); err != nil {
-				log.Println("Error cleaning up categories:", err)
-			}
-			if err := productRepo.CleanUpOldDeleted(duration); err != nil {
-				log.Println("Error cleaning up products:", err)
-			}
-			log.Println("Cleanup routine finished.")
+		fmt.Fprintln(os.Stderr, "Invalid logging configuration:", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+	if loaded.File != "" {
+		logger.Info("Loaded config file", "file", loaded.File)
+	}
+
+	err = cmd.run(context.Background(), cfg, args[1:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		logger.Error("Command failed", "command", cmd.name, "error", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [-config file] [command] [arguments]\n\nCommands:\n", os.Args[0])
+	tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", c.name, c.usage, c.summary)
+	}
+	fmt.Fprintln(tw, "  config print\tprint the effective configuration")
+	tw.Flush()
+	fmt.Fprintf(out, "\nRun %s <command> -h for the flags of a command.\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func unknownCommand(args []string) {
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", strings.Join(args, " "))
+	flag.Usage()
+	os.Exit(2)
+}
+
+// newFlagSet returns the flag set of a command. Parse errors are reported
+// by parseFlags rather than exiting.
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [-config file] %s %s\n", os.Args[0], name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args into fs and rejects positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
 		}
-	}()
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		return usageError(fs, "unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// usageError prints a problem with the arguments and the usage of fs.
+func usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(fs.Output(), format+"\n", args...)
+	fs.Usage()
+	return errUsage
+}
 
-	addr := ":" + config.Port
-	fmt.Println("Server is running on http://localhost" + addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
-		log.Fatal("Server failed to start:", err)
+// printConfig prints the effective configuration, then any validation errors.
+func printConfig(loaded *config.Loaded) {
+	if err := loaded.Print(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := loaded.Validate(); err != nil {
+		fmt.Fprint(os.Stderr, "\nInvalid configuration:\n"+bulletList(err))
+		os.Exit(1)
+	}
+}
+
+// bulletList formats each error joined into err on its own line.
+func bulletList(err error) string {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var b strings.Builder
+	for _, e := range errs {
+		b.WriteString("  - " + e.Error() + "\n")
 	}
+	return b.String()
 }
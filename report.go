@@ -0,0 +1,116 @@
+package main
+
+import (
+	"cateogry-api/internal/config"
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// report writes the sales report of [from, to), the same one GET /report
+// returns. Both dates default to today.
+func report(ctx context.Context, cfg config.Config, args []string) error {
+	fs := newFlagSet("report", "[-from date] [-to date] [-format csv|json]")
+	today := time.Now().Format(dateLayout)
+	fromStr := fs.String("from", today, "first day of the report, YYYY-MM-DD")
+	toStr := fs.String("to", "", "day after the last day of the report, YYYY-MM-DD (default: the day after -from)")
+	format := fs.String("format", "csv", "output format: csv or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	from, err := time.Parse(dateLayout, *fromStr)
+	if err != nil {
+		return usageError(fs, "invalid -from %q (YYYY-MM-DD)", *fromStr)
+	}
+	to := from.AddDate(0, 0, 1)
+	if *toStr != "" {
+		if to, err = time.Parse(dateLayout, *toStr); err != nil {
+			return usageError(fs, "invalid -to %q (YYYY-MM-DD)", *toStr)
+		}
+	}
+	if !to.After(from) {
+		return usageError(fs, "-to must be after -from")
+	}
+	if *format != "csv" && *format != "json" {
+		return usageError(fs, "unknown -format %q", *format)
+	}
+
+	db, err := connect(ctx, cfg.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r, err := repository.NewPostgresTransactionRepository(db).GetReport(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return writeReportCSV(os.Stdout, r)
+}
+
+// writeReportCSV writes the report as section,name,metric,value rows, one per
+// figure, so that every part of it fits in a single sheet. Metrics are named
+// as in the JSON report.
+func writeReportCSV(w io.Writer, r domain.DailyReport) error {
+	cw := csv.NewWriter(w)
+	row := func(section, name, metric, value string) {
+		cw.Write([]string{section, name, metric, value})
+	}
+	num := strconv.Itoa
+	pct := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+
+	row("section", "name", "metric", "value")
+	row("summary", "", "total_revenue", num(r.TotalRevenue))
+	row("summary", "", "total_transaksi", num(r.TotalTransactions))
+	row("summary", "", "total_diskon", num(r.TotalDiscount))
+	row("summary", "", "total_pajak", num(r.TotalTax))
+	row("summary", "", "penjualan_bersih", num(r.NetSales))
+	row("summary", "", "total_hpp", num(r.TotalCOGS))
+	row("summary", "", "laba_kotor", num(r.GrossProfit))
+	row("summary", "", "margin_persen", pct(r.MarginPercent))
+	if r.BestSellingProduct.Name != "" {
+		row("produk_terlaris", r.BestSellingProduct.Name, "qty_terjual", num(r.BestSellingProduct.QtySold))
+	}
+	for _, p := range r.Promotions {
+		row("promo", p.Name, "digunakan", num(p.TimesApplied))
+		row("promo", p.Name, "total_diskon", num(p.TotalDiscount))
+	}
+	for _, t := range r.Taxes {
+		name := strconv.Itoa(t.Rate)
+		row("pajak", name, "dasar_pengenaan", num(t.TaxableAmount))
+		row("pajak", name, "pajak", num(t.TaxAmount))
+	}
+	for _, m := range r.PaymentMethods {
+		row("metode_pembayaran", string(m.Method), "jumlah_transaksi", num(m.Transactions))
+		row("metode_pembayaran", string(m.Method), "total", num(m.Amount))
+	}
+	for _, m := range r.ProductMargins {
+		row("margin_produk", m.Name, "qty_terjual", num(m.QtySold))
+		row("margin_produk", m.Name, "penjualan_bersih", num(m.NetSales))
+		row("margin_produk", m.Name, "hpp", num(m.COGS))
+		row("margin_produk", m.Name, "laba_kotor", num(m.GrossProfit))
+		row("margin_produk", m.Name, "margin_persen", pct(m.MarginPercent))
+	}
+	for _, m := range r.CategoryMargins {
+		row("margin_kategori", m.Name, "qty_terjual", num(m.QtySold))
+		row("margin_kategori", m.Name, "penjualan_bersih", num(m.NetSales))
+		row("margin_kategori", m.Name, "hpp", num(m.COGS))
+		row("margin_kategori", m.Name, "laba_kotor", num(m.GrossProfit))
+		row("margin_kategori", m.Name, "margin_persen", pct(m.MarginPercent))
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"cateogry-api/internal/config"
+	"cateogry-api/internal/domain"
+	"cateogry-api/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// sampleProducts are seeded under the category of the same name. Prices are
+// in rupiah.
+var sampleProducts = map[string][]domain.Product{
+	"Electronics": {
+		{Name: "Wireless Mouse", Description: "2.4 GHz optical mouse", Price: 150000, CostPrice: 95000, Stock: 50},
+		{Name: "USB-C Charger", Description: "65 W fast charger", Price: 250000, CostPrice: 160000, Stock: 30},
+		{Name: "Mechanical Keyboard", Description: "Tenkeyless, brown switches", Price: 850000, CostPrice: 600000, Stock: 15},
+	},
+	"Books": {
+		{Name: "The Go Programming Language", Description: "Donovan and Kernighan", Price: 650000, CostPrice: 450000, Stock: 20},
+		{Name: "Clean Code", Description: "Robert C. Martin", Price: 450000, CostPrice: 300000, Stock: 25},
+	},
+	"Clothing": {
+		{Name: "Cotton T-Shirt", Description: "Plain crew neck", Price: 120000, CostPrice: 60000, Stock: 100},
+		{Name: "Denim Jacket", Description: "Classic blue denim", Price: 550000, CostPrice: 330000, Stock: 20},
+	},
+}
+
+// seed inserts the categories of the in-memory demo repository and a few
+// products in each. Categories and products that already exist by name are
+// left alone, so seeding twice adds nothing.
+func seed(ctx context.Context, cfg config.Config, args []string) error {
+	fs := newFlagSet("seed", "")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	db, err := connect(ctx, cfg.DB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	categoryRepo := repository.NewPostgresCategoryRepository(db)
+	productRepo := repository.NewPostgresProductRepository(db)
+
+	existingCategories, err := categoryRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	categoryIDs := make(map[string]int)
+	for _, c := range existingCategories {
+		categoryIDs[strings.ToLower(c.Name)] = c.ID
+	}
+	existingProducts, err := productRepo.GetAll(ctx, "")
+	if err != nil {
+		return err
+	}
+	productExists := make(map[string]bool)
+	for _, p := range existingProducts {
+		productExists[strings.ToLower(p.Name)] = true
+	}
+
+	demo, err := repository.NewInMemoryCategoryRepository().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	var categories, products int
+	for _, c := range demo {
+		id, ok := categoryIDs[strings.ToLower(c.Name)]
+		if !ok {
+			created, err := categoryRepo.Create(ctx, domain.Category{Name: c.Name, Description: c.Description})
+			if err != nil {
+				return fmt.Errorf("creating category %s: %w", c.Name, err)
+			}
+			id = created.ID
+			categories++
+		}
+
+		for _, p := range sampleProducts[c.Name] {
+			if productExists[strings.ToLower(p.Name)] {
+				continue
+			}
+			p.CategoryID = id
+			if _, err := productRepo.Create(ctx, p); err != nil {
+				return fmt.Errorf("creating product %s: %w", p.Name, err)
+			}
+			products++
+		}
+	}
+	fmt.Printf("Seeded %d categories and %d products\n", categories, products)
+	return nil
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"cateogry-api/database"
+	"cateogry-api/internal/config"
+	"cateogry-api/internal/handler"
+	"cateogry-api/internal/metrics"
+	"cateogry-api/internal/repository"
+	"cateogry-api/internal/service"
+	"cateogry-api/internal/tracing"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"cateogry-api/docs"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// serve runs the HTTP API and its background jobs until SIGTERM or SIGINT.
+func serve(ctx context.Context, cfg config.Config, args []string) error {
+	fs := newFlagSet("serve", "")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	logger := slog.Default()
+
+	taxRounding, err := service.ParseRoundingMode(cfg.Tax.Rounding)
+	if err != nil {
+		return fmt.Errorf("invalid tax.rounding: %w", err)
+	}
+
+	authSettings := service.AuthSettings{
+		JWTSecret:       []byte(cfg.Auth.JWTSecret),
+		JWTIssuer:       cfg.Auth.JWTIssuer,
+		JWTAudience:     cfg.Auth.JWTAudience,
+		BootstrapAPIKey: cfg.Auth.BootstrapAPIKey,
+	}
+	if cfg.Auth.JWTPublicKey != "" {
+		// Either the PEM itself or the path of a PEM file
+		pem := []byte(cfg.Auth.JWTPublicKey)
+		if !strings.HasPrefix(cfg.Auth.JWTPublicKey, "-----BEGIN") {
+			if pem, err = os.ReadFile(cfg.Auth.JWTPublicKey); err != nil {
+				return fmt.Errorf("cannot read auth.jwt_public_key: %w", err)
+			}
+		}
+		if authSettings.JWTPublicKey, err = service.ParseRSAPublicKey(pem); err != nil {
+			return fmt.Errorf("invalid auth.jwt_public_key: %w", err)
+		}
+	}
+
+	rolePermissions, err := service.ParseRolePermissions(cfg.Auth.RolePermissions)
+	if err != nil {
+		return fmt.Errorf("invalid auth.role_permissions: %w", err)
+	}
+	authz := service.NewAuthorizer(rolePermissions)
+
+	// Setup Tracing
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Settings{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid tracing configuration: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Warn("Failed to flush traces", "error", err)
+		}
+	}()
+
+	// SIGTERM or SIGINT starts the drain; a second signal kills the process
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	// Background work stops when jobsCtx is cancelled at shutdown; a run in
+	// progress has its queries cancelled and is waited for
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+
+	// Setup Database
+	dbs := dbSettings(cfg.DB)
+	db, err := database.InitDB(ctx, cfg.DB.Conn, dbs)
+	if db == nil {
+		return fmt.Errorf("invalid database configuration: %w", err)
+	}
+	metrics.RegisterDB(db, "postgres")
+	migrate := func(ctx context.Context) {
+		if err := database.Migrate(ctx, db); err != nil {
+			logger.Warn("Failed to apply database migrations", "error", err)
+		}
+	}
+	if err != nil {
+		// Serve anyway, reporting not ready, and keep trying in the background
+		logger.Warn("Database unavailable, reconnecting in the background", "error", err)
+		retry := dbs.Retry
+		retry.Retries = -1
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			if database.WaitForDB(jobsCtx, db, retry) == nil {
+				logger.Info("Database connected successfully")
+				migrate(jobsCtx)
+			}
+		}()
+	} else {
+		migrate(ctx)
+	}
+
+	// Health Check
+	healthHandler := handler.NewHealthHandler(db)
+
+	// Auth Dependency Injection
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(db)
+	authSvc := service.NewAuthService(apiKeyRepo, authz, authSettings)
+	apiKeyHandler := handler.NewAPIKeyHandler(authSvc)
+
+	// Audit Dependency Injection
+	auditRepo := repository.NewPostgresAuditRepository(db)
+	auditSvc := service.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditSvc)
+
+	// Category Dependency Injection
+	categoryRepo := repository.NewPostgresCategoryRepository(db)
+	categorySvc := service.NewCategoryService(categoryRepo, auditSvc)
+	categoryHandler := handler.NewCategoryHandler(categorySvc)
+
+	// Product Dependency Injection
+	productRepo := repository.NewPostgresProductRepository(db)
+	productSvc := service.NewProductService(productRepo, auditSvc)
+	productHandler := handler.NewProductHandler(productSvc)
+
+	// Location Dependency Injection
+	locationRepo := repository.NewPostgresLocationRepository(db)
+	locationSvc := service.NewLocationService(locationRepo)
+	locationHandler := handler.NewLocationHandler(locationSvc)
+
+	// Supplier Dependency Injection
+	supplierRepo := repository.NewPostgresSupplierRepository(db)
+	supplierSvc := service.NewSupplierService(supplierRepo)
+	supplierHandler := handler.NewSupplierHandler(supplierSvc)
+
+	// Purchase Order Dependency Injection
+	purchaseOrderRepo := repository.NewPostgresPurchaseOrderRepository(db)
+	purchaseOrderSvc := service.NewPurchaseOrderService(purchaseOrderRepo)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(purchaseOrderSvc)
+
+	// Promotion Dependency Injection
+	promotionRepo := repository.NewPostgresPromotionRepository(db)
+	promotionSvc := service.NewPromotionService(promotionRepo)
+	promotionHandler := handler.NewPromotionHandler(promotionSvc)
+
+	// Tax Dependency Injection
+	taxRepo := repository.NewPostgresTaxRateRepository(db)
+	taxSvc := service.NewTaxService(taxRepo)
+	taxHandler := handler.NewTaxHandler(taxSvc)
+	taxSettings := service.TaxSettings{PricesIncludeTax: cfg.Tax.PricesIncludeTax, Rounding: taxRounding}
+
+	// Transaction Dependency Injection
+	transactionRepo := repository.NewPostgresTransactionRepository(db)
+	transactionSvc := service.NewTransactionService(transactionRepo, promotionRepo, taxRepo, taxSettings, auditSvc)
+	transactionHandler := handler.NewTransactionHandler(transactionSvc)
+
+	// Reservation Dependency Injection
+	reservationRepo := repository.NewPostgresReservationRepository(db)
+	reservationSvc := service.NewReservationService(reservationRepo, cfg.Reservation.TTL, cfg.Reservation.MaxTTL)
+	reservationHandler := handler.NewReservationHandler(reservationSvc)
+
+	// Cart Dependency Injection
+	cartRepo := repository.NewPostgresCartRepository(db)
+	cartSvc := service.NewCartService(cartRepo, productRepo, transactionSvc, cfg.Cart.TTL)
+	cartHandler := handler.NewCartHandler(cartSvc)
+
+	// API Versioning Setup
+	v1Mux := http.NewServeMux()
+	apiKeyHandler.RegisterRoutes(v1Mux, authz)
+	auditHandler.RegisterRoutes(v1Mux, authz)
+	categoryHandler.RegisterRoutes(v1Mux, authz)
+	productHandler.RegisterRoutes(v1Mux, authz)
+	locationHandler.RegisterRoutes(v1Mux, authz)
+	supplierHandler.RegisterRoutes(v1Mux, authz)
+	purchaseOrderHandler.RegisterRoutes(v1Mux, authz)
+	promotionHandler.RegisterRoutes(v1Mux, authz)
+	taxHandler.RegisterRoutes(v1Mux, authz)
+	transactionHandler.RegisterRoutes(v1Mux, authz)
+	cartHandler.RegisterRoutes(v1Mux, authz)
+	reservationHandler.RegisterRoutes(v1Mux, authz)
+	healthHandler.RegisterRoutes(v1Mux, authz)
+
+	// Main Router
+	mux := http.NewServeMux()
+	// Public: health probes and the metrics scrape endpoint
+	healthHandler.RegisterProbes(mux, "/api/v1")
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", handler.RequestTimeout(cfg.Server.RequestTimeout, handler.RequireAuth(authSvc, v1Mux))))
+
+	// Swagger Setup (Root Level)
+	docs.SwaggerInfo.Host = cfg.Swagger.Host
+	mux.Handle("/swagger/", httpSwagger.Handler(
+		httpSwagger.URL("/swagger/doc.json"), // The url pointing to API definition
+	))
+
+	jobFinished := func(job string, err error) {
+		metrics.JobFinished(job, err)
+		healthHandler.JobFinished(job, err)
+	}
+
+	// Start Background Cleanup Routine (purge records deleted longer ago than the retention)
+	every(jobsCtx, &jobs, cfg.Cleanup.Interval, func(ctx context.Context) {
+		logger.Info("Running cleanup routine")
+		for _, step := range cleanupSteps(db, cfg.Cleanup.Retention) {
+			err := step.run(ctx)
+			jobFinished(step.job, err)
+			if err != nil {
+				logger.Error("Cleanup step failed", "job", step.job, "error", err)
+			}
+		}
+		logger.Info("Cleanup routine finished")
+	})
+
+	// Start Reservation Expirer (release holds past their TTL)
+	every(jobsCtx, &jobs, cfg.Reservation.ExpiryInterval, func(ctx context.Context) {
+		expired, err := reservationSvc.ExpireStale(ctx)
+		jobFinished("expire_reservations", err)
+		if err != nil {
+			logger.Error("Error expiring stock reservations", "error", err)
+			return
+		}
+		metrics.ReservationsExpired(expired)
+		if expired > 0 {
+			logger.Info("Expired stock reservations", "count", expired)
+		}
+	})
+
+	// Middleware: the trace span wraps everything so request logs carry its ID
+	route := metrics.MuxRoute(mux, "/api/v1", v1Mux)
+	cors := handler.CORSSettings{
+		AllowedOrigins: cfg.CORS.AllowedOrigins,
+		AllowedMethods: cfg.CORS.AllowedMethods,
+		AllowedHeaders: cfg.CORS.AllowedHeaders,
+		MaxAge:         cfg.CORS.MaxAge,
+	}
+	var root http.Handler = handler.RequestID(handler.LogRequests(logger, handler.CORS(cors, metrics.Instrument(route, mux))))
+	root = otelhttp.NewHandler(root, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + route(r)
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/metrics"
+		}),
+	)
+
+	server := &http.Server{
+		Addr:           ":" + cfg.Server.Port,
+		Handler:        root,
+		ReadTimeout:    cfg.Server.ReadTimeout,
+		WriteTimeout:   cfg.Server.WriteTimeout,
+		IdleTimeout:    cfg.Server.IdleTimeout,
+		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+		ErrorLog:       slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	logger.Info("Server is running", "url", "http://localhost"+server.Addr)
+
+	select {
+	case err := <-serverErr:
+		stopJobs()
+		jobs.Wait()
+		db.Close()
+		return fmt.Errorf("server failed to start: %w", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Report not ready first, so load balancers stop routing here during
+	// the delay, then stop accepting connections and let in-flight requests
+	// finish
+	logger.Info("Shutting down", "delay", cfg.Shutdown.Delay.String(), "drain_timeout", cfg.Shutdown.Timeout.String())
+	healthHandler.Drain()
+	time.Sleep(cfg.Shutdown.Delay)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
+	defer cancelDrain()
+	if err := server.Shutdown(drainCtx); err != nil {
+		logger.Warn("Requests still running after the drain period", "error", err)
+	}
+
+	stopJobs()
+	jobs.Wait()
+
+	if err := db.Close(); err != nil {
+		logger.Warn("Error closing database", "error", err)
+	}
+	logger.Info("Server stopped")
+	return nil
+}
+
+// every runs job each interval until ctx is cancelled. wg tracks the loop so
+// shutdown can wait for a run in progress.
+func every(ctx context.Context, wg *sync.WaitGroup, interval time.Duration, job func(context.Context)) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job(ctx)
+			}
+		}
+	}()
+}